@@ -1,10 +1,20 @@
 package main
 
 import (
+	"NewsChannel/news"
 	"math"
 	"unicode/utf16"
 )
 
+// articleIDsPerHour is the size of the range of article IDs given to each hour.
+// As the timestamp table refers to articles from the last 24 hours, this keeps IDs unique across all of them.
+const articleIDsPerHour = 1000
+
+// articleID returns the ID of the article at the given index in the file generated at the given hour.
+func articleID(hour int, index int) uint32 {
+	return uint32(hour*articleIDsPerHour + index + 1)
+}
+
 type Article struct {
 	ID                uint32
 	SourceIndex       uint32
@@ -33,13 +43,13 @@ func (n *News) MakeArticleTable() {
 
 	// First write all metadata
 	for i, article := range n.articles {
-		publishedTime := currentTime
+		publishedTime, updatedTime := articleTimes(article)
 
 		// Parse the location if any.
 		locationIndex := uint32(math.MaxUint32)
 		if article.Location != nil {
 			for i, location := range n.locations {
-				if floatCompare(location.Latitude, article.Location.Latitude) && floatCompare(location.Longitude, article.Location.Longitude) {
+				if isSameLocation(location, article.Location) {
 					locationIndex = uint32(i)
 					break
 				}
@@ -53,22 +63,23 @@ func (n *News) MakeArticleTable() {
 		}
 
 		n.Articles = append(n.Articles, Article{
-			ID:                uint32(i + 1),
+			ID:                articleID(n.currentHour, i),
 			SourceIndex:       0,
 			LocationIndex:     locationIndex,
 			PictureTimestamp:  0,
 			PictureIndex:      math.MaxUint32,
 			PublishedTime:     fixTime(publishedTime),
-			UpdatedTime:       fixTime(currentTime),
+			UpdatedTime:       fixTime(updatedTime),
 			HeadlineSize:      0,
 			HeadlineOffset:    0,
 			ArticleTextSize:   0,
 			ArticleTextOffset: 0,
 		})
 
-		n.timestamps[article.Topic+1] = append(n.timestamps[article.Topic+1], Timestamp{
-			Time:          fixTime(currentTime),
-			ArticleNumber: uint32(i + 1),
+		index, _ := n.topicIndex(article.Topic)
+		n.timestamps[index] = append(n.timestamps[index], Timestamp{
+			Time:          fixTime(publishedTime),
+			ArticleNumber: articleID(n.currentHour, i),
 		})
 	}
 
@@ -104,62 +115,116 @@ func (n *News) MakeArticleTable() {
 	n.Header.NumberOfArticles = uint32(len(n.Articles))
 }
 
-func (n *News) WriteImages() {
-	n.Header.ImagesTableOffset = n.GetCurrentSize()
-	for _, article := range n.articles {
-		if article.Thumbnail == nil || len(article.Thumbnail.Image) == 0 {
-			continue
+// articleTimes returns when an article was published and last updated as Unix timestamps.
+// Sources that don't give a time get the current time, and times in the future are clamped to it.
+func articleTimes(article news.Article) (int, int) {
+	publishedTime := currentTime
+	if !article.PublishedTime.IsZero() {
+		publishedTime = min(int(article.PublishedTime.Unix()), currentTime)
+	}
+
+	updatedTime := publishedTime
+	if !article.UpdatedTime.IsZero() {
+		updatedTime = min(max(int(article.UpdatedTime.Unix()), publishedTime), currentTime)
+	}
+
+	return publishedTime, updatedTime
+}
+
+// choosePictures picks the picture shown with each article and returns the pictures to write into the image table.
+// The console shows a single picture per article, so it is the first of the article's pictures that fits in what is
+// left of the image budget. The others are only downloaded if the ones before them don't fit. Articles sharing a
+// picture share its entry in the table. Each article is left with only the picture written with it, if any, so that
+// is what the store refers to.
+func (n *News) choosePictures() []news.Thumbnail {
+	var pictures []news.Thumbnail
+	indexes := make(map[string]int)
+	used := 0
+	for j := range n.articles {
+		candidates := n.articles[j].Pictures
+		n.articles[j].Pictures = nil
+		for k := range candidates {
+			picture := &candidates[k]
+			if !picture.Load() {
+				continue
+			}
+
+			index, ok := indexes[string(picture.Image)]
+			if !ok {
+				if imageBudget > 0 && used+len(picture.Image) > imageBudget {
+					continue
+				}
+
+				index = len(pictures)
+				indexes[string(picture.Image)] = index
+				pictures = append(pictures, *picture)
+				used += len(picture.Image)
+			}
+
+			// Fix up the article
+			n.Articles[j].PictureIndex = uint32(index)
+			n.Articles[j].PictureTimestamp = fixTime(currentTime)
+			n.articles[j].Pictures = candidates[k : k+1]
+			break
 		}
+	}
 
+	return pictures
+}
+
+func (n *News) WriteImages() {
+	pictures := n.choosePictures()
+
+	n.Header.ImagesTableOffset = n.GetCurrentSize()
+	for _, picture := range pictures {
 		n.Images = append(n.Images, Image{
 			CreditSize:    0,
 			CreditOffset:  0,
 			CaptionSize:   0,
 			CaptionOffset: 0,
-			PictureSize:   uint32(len(article.Thumbnail.Image)),
+			PictureSize:   uint32(len(picture.Image)),
 			PictureOffset: 0,
 		})
 	}
 
-	i := 0
-	for j, article := range n.articles {
-		if article.Thumbnail == nil || len(article.Thumbnail.Image) == 0 {
-			continue
-		}
-
+	for i, picture := range pictures {
 		n.Images[i].PictureOffset = n.GetCurrentSize()
-		n.ImagesData = append(n.ImagesData, article.Thumbnail.Image...)
+		n.ImagesData = append(n.ImagesData, picture.Image...)
 		for n.GetCurrentSize()%4 != 0 {
 			n.ImagesData = append(n.ImagesData, 0)
 		}
-
-		// Fix up the article
-		n.Articles[j].PictureIndex = uint32(i)
-		n.Articles[j].PictureTimestamp = fixTime(currentTime)
-		i++
 	}
 
-	i = 0
-	for _, article := range n.articles {
-		if article.Thumbnail == nil || len(article.Thumbnail.Image) == 0 {
-			continue
-		}
-		if len(article.Thumbnail.Caption) == 0 {
-			i++
+	for i, picture := range pictures {
+		if len(picture.Caption) == 0 {
 			continue
 		}
 
-		caption := utf16.Encode([]rune(article.Thumbnail.Caption))
+		caption := utf16.Encode([]rune(picture.Caption))
 		n.Images[i].CaptionOffset = n.GetCurrentSize()
-		n.Images[i].CaptionSize = uint32(len(caption) / 2)
+		n.Images[i].CaptionSize = uint32(len(caption) * 2)
 		n.CaptionData = append(n.CaptionData, caption...)
 		n.CaptionData = append(n.CaptionData, 0)
 
 		for n.GetCurrentSize()%4 != 0 {
 			n.CaptionData = append(n.CaptionData, uint16(0))
 		}
+	}
 
-		i++
+	for i, picture := range pictures {
+		if len(picture.Credit) == 0 {
+			continue
+		}
+
+		credit := utf16.Encode([]rune(picture.Credit))
+		n.Images[i].CreditOffset = n.GetCurrentSize()
+		n.Images[i].CreditSize = uint32(len(credit) * 2)
+		n.CreditData = append(n.CreditData, credit...)
+		n.CreditData = append(n.CreditData, 0)
+
+		for n.GetCurrentSize()%4 != 0 {
+			n.CreditData = append(n.CreditData, uint16(0))
+		}
 	}
 
 	n.Header.NumberOfImages = uint32(len(n.Images))
@@ -1,20 +1,94 @@
 package main
 
-func (n *News) GetTopicsForLanguage() []string {
+import (
+	"NewsChannel/news"
+	"fmt"
+	"slices"
+)
+
+// GetLanguageTag returns the ISO 639-1 code of the current language.
+func (n *News) GetLanguageTag() string {
 	switch n.currentLanguageCode {
 	case 0:
-		return []string{"社会", "国際", "スポーツ", "芸能文化", "経済", "科学", "テクノロジー"}
+		return "ja"
 	case 2:
-		return []string{"Inland", "Weltweit", "Sport", "Feuilleton", "Wirtschaft", "Wissenschaft/Gesundheit", "Technologie"}
+		return "de"
 	case 3:
-		return []string{"Actualités nationales", "Actualités internationales", "Sport", "Arts & loisirs", "Economie", "Sciences & santé", "Technologie"}
+		return "fr"
 	case 4:
-		return []string{"Nacional", "Internacional", "Deportes", "Cultura y ocio", "Economia", "Ciencia y salud", "Tecnologia"}
+		return "es"
 	case 5:
-		return []string{"Notizie nazionali", "Notizie internazionali", "Sport", "Arte e Intrattenimento", "Attività commerciale", "Scienze e salute", "Tecnologia"}
+		return "it"
 	case 6:
-		return []string{"Continentaal nieuws", "Internationaal nieuws", "Sport", "Kunst en entertainment", "Zakelljk nieuws", "Wetenschap en gezondheid", "Technologie"}
+		return "nl"
 	default:
-		return []string{"National News", "International News", "Sports", "Arts/Entertainment", "Business", "Science/Health", "Technology"}
+		return "en"
+	}
+}
+
+// languageNames are the names countries.json gives each language, indexed by language code.
+var languageNames = []string{"Japanese", "English", "German", "French", "Spanish", "Italian", "Dutch"}
+
+// LanguageCodes returns the codes of the languages the console offers for a country, in the order they are listed.
+// Unknown language names are left out, which validate reports.
+func (c CountryConfig) LanguageCodes() []uint8 {
+	var languages []uint8
+	for _, name := range c.Languages {
+		if code := slices.Index(languageNames, name); code != -1 {
+			languages = append(languages, uint8(code))
+		}
+	}
+
+	return languages
+}
+
+// TopicLabel is a topic shown in the channel along with its name.
+type TopicLabel struct {
+	Topic news.Topic
+	Name  string
+}
+
+// TopicsFor returns the topics a country shows, in the order of the topic table.
+func (c *Countries) TopicsFor(country CountryConfig) ([]TopicLabel, error) {
+	names := c.TopicNames[country.Language]
+
+	configs := country.Topics
+	if len(configs) == 0 {
+		for _, topic := range news.BuiltinTopics {
+			configs = append(configs, TopicConfig{Key: topic.String()})
+		}
+	}
+
+	var labels []TopicLabel
+	for _, config := range configs {
+		topic, err := news.ParseTopic(config.Key)
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(labels, func(label TopicLabel) bool { return label.Topic == topic }) {
+			return nil, fmt.Errorf("topic %q is listed twice", config.Key)
+		}
+
+		name := config.Name
+		if name == "" {
+			name = names[config.Key]
+		}
+		if name == "" {
+			return nil, fmt.Errorf("topic %q has no name in %s", config.Key, country.Language)
+		}
+
+		labels = append(labels, TopicLabel{Topic: topic, Name: name})
 	}
+
+	return labels, nil
+}
+
+// topicIndex returns the index of a topic in the topic table, or false if the country hides it.
+// The first entry of the table is unused, so the first topic is at index 1.
+func (n *News) topicIndex(topic news.Topic) (int, bool) {
+	index := slices.IndexFunc(n.topicLabels, func(label TopicLabel) bool {
+		return label.Topic == topic
+	})
+
+	return index + 1, index != -1
 }
@@ -0,0 +1,498 @@
+package main
+
+import (
+	"NewsChannel/news"
+	"NewsChannel/store"
+	"bytes"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// fakeSource returns fixed articles, so the binary format can be checked without fetching anything.
+type fakeSource struct {
+	articles []news.Article
+}
+
+func (f fakeSource) GetArticles() ([]news.Article, error) {
+	return f.articles, nil
+}
+
+func (f fakeSource) GetLogo() []byte {
+	return []byte{0xff, 0xd8, 0xff, 0xd9, 0x00}
+}
+
+func (f fakeSource) GetCopyright() []uint16 {
+	return utf16.Encode([]rune("© Fake News Agency 2025"))
+}
+
+func fakeArticles() []news.Article {
+	content := []string{
+		"The first article.\n\nIt has two paragraphs.",
+		"Ünïcödé and 🗞️ characters outside of the basic plane.",
+		"An article without a picture.",
+		"An article whose picture has neither caption nor credit.",
+	}
+
+	return []news.Article{
+		{
+			Title:    "Council approves new harbour bridge",
+			Content:  &content[0],
+			Topic:    news.NationalNews,
+			Location: &news.Location{Name: "Tokyo", Latitude: 35.68, Longitude: 139.69},
+			Pictures: []news.Thumbnail{
+				{Image: []byte{1, 2, 3, 4, 5, 6}, Caption: "The bridge at night.", Credit: "REUTERS/Jane Doe"},
+				{Image: []byte{14, 15}, Caption: "The old bridge."},
+			},
+		},
+		{
+			Title:    "Zeitung 🗞️ über Münchens Straßenbahn",
+			Content:  &content[1],
+			Topic:    news.InternationalNews,
+			Location: &news.Location{Name: "München", Latitude: 48.14, Longitude: 11.58},
+			Pictures: []news.Thumbnail{{Image: []byte{7, 8, 9}, Caption: "Eine 🚋 in München", Credit: "picture alliance/dpa"}},
+		},
+		{
+			Title:   "Football season opens with a derby",
+			Content: &content[2],
+			Topic:   news.Sports,
+		},
+		{
+			Title:    "Film festival announces its jury",
+			Content:  &content[3],
+			Topic:    news.Entertainment,
+			Pictures: []news.Thumbnail{{Image: []byte{10, 11, 12, 13}}},
+		},
+	}
+}
+
+// makeFakeNews generates a news file from fakeArticles for a country showing topics, and returns it along with the
+// articles written.
+func makeFakeNews(t *testing.T, topics []TopicLabel) ([]byte, []news.Article) {
+	articleStore, err := store.Open(filepath.Join(t.TempDir(), "articles.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer articleStore.Close()
+
+	n := News{}
+	n.articleStore = articleStore
+	n.currentCountryCode = 49
+	n.currentLanguageCode = 1
+	n.countryName = "United States"
+	n.supportedLanguages = []uint8{1}
+	n.topicLabels = topics
+	currentTime = int(time.Date(2025, 6, 1, 12, 30, 0, 0, time.Local).Unix())
+	n.currentHour = 12
+
+	err = n.ReadNewsCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n.source = fakeSource{articles: fakeArticles()}
+	err = n.GetNewsArticles()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n.MakeHeader()
+	n.MakeWiiMenuHeadlines()
+	n.MakeArticleTable()
+	n.MakeTopicTable()
+	n.MakeSourceTable()
+	n.MakeLocationTable()
+	n.WriteImages()
+	n.Header.Filesize = n.GetCurrentSize()
+
+	buffer := new(bytes.Buffer)
+	n.WriteAll(buffer)
+	return buffer.Bytes(), n.articles
+}
+
+// newsFile decodes the parts of a news file.
+type newsFile struct {
+	t    *testing.T
+	data []byte
+}
+
+// read decodes the value at offset.
+func (f newsFile) read(offset uint32, value any) {
+	f.t.Helper()
+	if int(offset) > len(f.data) {
+		f.t.Fatalf("offset %d is past the end of the file", offset)
+	}
+
+	err := binary.Read(bytes.NewReader(f.data[offset:]), binary.BigEndian, value)
+	if err != nil {
+		f.t.Fatalf("failed to read at offset %d: %v", offset, err)
+	}
+}
+
+// text decodes UTF-16 text of size bytes at offset. Every text is aligned to 4 bytes, and followed by a null
+// terminator that isn't part of its size.
+func (f newsFile) text(name string, offset uint32, size uint32) string {
+	f.t.Helper()
+	if offset%4 != 0 {
+		f.t.Errorf("%s: offset %d is not aligned to 4 bytes", name, offset)
+	}
+	if size%2 != 0 {
+		f.t.Errorf("%s: size %d is not a whole number of UTF-16 units", name, size)
+	}
+
+	units := make([]uint16, size/2+1)
+	f.read(offset, units)
+	if units[len(units)-1] != 0 {
+		f.t.Errorf("%s: text of %d bytes at %d is not followed by a null terminator", name, size, offset)
+	}
+
+	return string(utf16.Decode(units[:len(units)-1]))
+}
+
+// terminatedText decodes null terminated UTF-16 text at offset, for tables without a size.
+func (f newsFile) terminatedText(name string, offset uint32) string {
+	f.t.Helper()
+	if offset%4 != 0 {
+		f.t.Errorf("%s: offset %d is not aligned to 4 bytes", name, offset)
+	}
+
+	var units []uint16
+	for i := offset; int(i)+1 < len(f.data); i += 2 {
+		unit := binary.BigEndian.Uint16(f.data[i:])
+		if unit == 0 {
+			return string(utf16.Decode(units))
+		}
+		units = append(units, unit)
+	}
+
+	f.t.Errorf("%s: text at %d is not null terminated", name, offset)
+	return ""
+}
+
+// englishTopics returns the topics of a country showing every topic in English.
+func englishTopics(t *testing.T) []TopicLabel {
+	countries, err := LoadCountries("countries.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topics, err := countries.TopicsFor(CountryConfig{Name: "United States", Language: "English"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return topics
+}
+
+func TestNewsFileFormat(t *testing.T) {
+	data, articles := makeFakeNews(t, englishTopics(t))
+	file := newsFile{t, data}
+
+	var header Header
+	file.read(0, &header)
+	if int(header.Filesize) != len(data) {
+		t.Errorf("expected a file size of %d, got %d", len(data), header.Filesize)
+	}
+	if header.SupportedLanguages[0] != 1 || header.SupportedLanguages[1] != 0xFF || header.ShowLanguageSelectScreen != 0 {
+		t.Errorf("expected English alone, got %v", header.SupportedLanguages)
+	}
+
+	// Headlines
+	headlines := make([]Headlines, header.NumberOfHeadlines)
+	file.read(header.HeadlinesTableOffset, headlines)
+	if len(headlines) != len(articles) {
+		t.Fatalf("expected %d headlines, got %d", len(articles), len(headlines))
+	}
+	for i, headline := range headlines {
+		if text := file.text("headline", headline.HeadlineOffset, headline.HeadlineSize); text != articles[i].Title {
+			t.Errorf("headline %d: expected %q, got %q", i, articles[i].Title, text)
+		}
+	}
+
+	// Articles
+	articleTable := make([]Article, header.NumberOfArticles)
+	file.read(header.ArticleTableOffset, articleTable)
+	if len(articleTable) != len(articles) {
+		t.Fatalf("expected %d articles, got %d", len(articles), len(articleTable))
+	}
+	for i, article := range articleTable {
+		if text := file.text("article headline", article.HeadlineOffset, article.HeadlineSize); text != articles[i].Title {
+			t.Errorf("article %d: expected headline %q, got %q", i, articles[i].Title, text)
+		}
+		if text := file.text("article text", article.ArticleTextOffset, article.ArticleTextSize); text != *articles[i].Content {
+			t.Errorf("article %d: expected text %q, got %q", i, *articles[i].Content, text)
+		}
+	}
+
+	// Topics
+	topics := make([]Topic, header.NumberOfTopics)
+	file.read(header.TopicTableOffset, topics)
+	names := []string{"National News", "International News", "Sports", "Arts/Entertainment", "Business", "Science/Health", "Technology"}
+	for i, topic := range topics[1:] {
+		if text := file.terminatedText("topic", topic.TextOffset); text != names[i] {
+			t.Errorf("topic %d: expected %q, got %q", i, names[i], text)
+		}
+
+		timestamps := make([]Timestamp, topic.NumberOfArticles)
+		file.read(topic.TimestampTableOffset, timestamps)
+		for _, timestamp := range timestamps {
+			index := int(timestamp.ArticleNumber) - int(articleID(12, 0))
+			if index < 0 || index >= len(articles) || articles[index].Topic != news.BuiltinTopics[i] {
+				t.Errorf("topic %d: unexpected article number %d", i, timestamp.ArticleNumber)
+			}
+		}
+	}
+
+	// Sources
+	sources := make([]Source, header.NumberOfSources)
+	file.read(header.SourceTableOffset, sources)
+	source := fakeSource{}
+	logo := make([]byte, sources[0].PictureSize)
+	file.read(sources[0].PictureOffset, logo)
+	if !bytes.Equal(logo, source.GetLogo()) {
+		t.Errorf("expected the source logo %x, got %x", source.GetLogo(), logo)
+	}
+	expectedCopyright := string(utf16.Decode(source.GetCopyright()))
+	if text := file.text("copyright", sources[0].CopyrightOffset, sources[0].CopyrightSize); text != expectedCopyright {
+		t.Errorf("expected the copyright %q, got %q", expectedCopyright, text)
+	}
+
+	// Locations
+	locations := make([]Location, header.NumberOfLocations)
+	file.read(header.LocationTableOffset, locations)
+	for i, article := range articleTable {
+		if articles[i].Location == nil {
+			continue
+		}
+
+		location := locations[article.LocationIndex]
+		if text := file.terminatedText("location", location.TextOffset); text != articles[i].Location.Name {
+			t.Errorf("article %d: expected the location %q, got %q", i, articles[i].Location.Name, text)
+		}
+	}
+
+	// Images
+	images := make([]Image, header.NumberOfImages)
+	file.read(header.ImagesTableOffset, images)
+	for i, article := range articleTable {
+		if len(articles[i].Pictures) == 0 {
+			if article.PictureIndex != 0xffffffff {
+				t.Errorf("article %d: expected no picture, got %d", i, article.PictureIndex)
+			}
+			continue
+		}
+
+		// The console shows the lead picture when it fits.
+		thumbnail := articles[i].Pictures[0]
+		image := images[article.PictureIndex]
+		picture := make([]byte, image.PictureSize)
+		file.read(image.PictureOffset, picture)
+		if !bytes.Equal(picture, thumbnail.Image) {
+			t.Errorf("article %d: expected the picture %x, got %x", i, thumbnail.Image, picture)
+		}
+
+		if thumbnail.Caption == "" {
+			if image.CaptionSize != 0 || image.CaptionOffset != 0 {
+				t.Errorf("article %d: expected no caption", i)
+			}
+		} else if text := file.text("caption", image.CaptionOffset, image.CaptionSize); text != thumbnail.Caption {
+			t.Errorf("article %d: expected the caption %q, got %q", i, thumbnail.Caption, text)
+		}
+
+		if thumbnail.Credit == "" {
+			if image.CreditSize != 0 || image.CreditOffset != 0 {
+				t.Errorf("article %d: expected no credit", i)
+			}
+		} else if text := file.text("credit", image.CreditOffset, image.CreditSize); text != thumbnail.Credit {
+			t.Errorf("article %d: expected the credit %q, got %q", i, thumbnail.Credit, text)
+		}
+	}
+}
+
+func TestImageBudget(t *testing.T) {
+	defer func(budget int) {
+		imageBudget = budget
+	}(imageBudget)
+	imageBudget = 10
+
+	shared := news.Thumbnail{Image: []byte{1, 2, 3}, Caption: "Shared"}
+	n := News{}
+	n.articles = []news.Article{
+		// The lead picture doesn't fit, so the next one is used.
+		{Pictures: []news.Thumbnail{{Image: make([]byte, 11)}, {Image: []byte{4, 5, 6, 7}}}},
+		{Pictures: []news.Thumbnail{shared}},
+		// Pictures already in the file don't count against the budget again.
+		{Pictures: []news.Thumbnail{shared}},
+		// Nothing fits anymore.
+		{Pictures: []news.Thumbnail{{Image: []byte{8, 9, 10, 11}}}},
+	}
+	for range n.articles {
+		n.Articles = append(n.Articles, Article{PictureIndex: 0xffffffff})
+	}
+
+	pictures := n.choosePictures()
+	if len(pictures) != 2 {
+		t.Fatalf("expected 2 pictures, got %d", len(pictures))
+	}
+	if !bytes.Equal(pictures[0].Image, []byte{4, 5, 6, 7}) || pictures[1].Caption != "Shared" {
+		t.Errorf("unexpected pictures %v", pictures)
+	}
+
+	expected := []uint32{0, 1, 1, 0xffffffff}
+	for i, article := range n.Articles {
+		if article.PictureIndex != expected[i] {
+			t.Errorf("article %d: expected picture %d, got %d", i, expected[i], article.PictureIndex)
+		}
+	}
+
+	// Articles only keep the picture written with them, which is what the store refers to.
+	if len(n.articles[0].Pictures) != 1 || !bytes.Equal(n.articles[0].Pictures[0].Image, []byte{4, 5, 6, 7}) {
+		t.Errorf("expected the first article to keep the picture that fits, got %v", n.articles[0].Pictures)
+	}
+	if n.articles[3].Pictures != nil {
+		t.Errorf("expected the last article to keep no picture, got %v", n.articles[3].Pictures)
+	}
+}
+
+func TestLazyPictures(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		_, _ = w.Write([]byte("not a picture"))
+	}))
+	defer server.Close()
+
+	defer func(budget int) {
+		imageBudget = budget
+	}(imageBudget)
+	imageBudget = 10
+
+	n := News{}
+	n.articles = []news.Article{
+		// The lead picture fits, so the gallery isn't downloaded.
+		{Pictures: []news.Thumbnail{{Image: []byte{1, 2, 3}}, news.LazyThumbnail([]string{server.URL + "/unused.jpg"}, "", "")}},
+		// The lead picture doesn't fit, so the next one is tried.
+		{Pictures: []news.Thumbnail{{Image: make([]byte, 11)}, news.LazyThumbnail([]string{server.URL + "/fallback.jpg"}, "", "")}},
+	}
+	n.Articles = make([]Article, len(n.articles))
+
+	pictures := n.choosePictures()
+	if len(pictures) != 1 || !slices.Equal(requested, []string{"/fallback.jpg"}) {
+		t.Errorf("expected only the fallback to be downloaded, got %d pictures after downloading %v", len(pictures), requested)
+	}
+}
+
+func TestTopicsFor(t *testing.T) {
+	countries := &Countries{
+		TopicNames: map[string]map[string]string{
+			"English": {"national": "National News", "sports": "Sports", "technology": "Technology"},
+		},
+	}
+
+	country := CountryConfig{Name: "Canada", Language: "English", Topics: []TopicConfig{
+		{Key: "sports"},
+		{Key: "national", Name: "Canadian News"},
+		{Key: "weather", Name: "Weather"},
+	}}
+	topics, err := countries.TopicsFor(country)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []TopicLabel{{news.Sports, "Sports"}, {news.NationalNews, "Canadian News"}, {"weather", "Weather"}}
+	if !slices.Equal(topics, expected) {
+		t.Errorf("expected %v, got %v", expected, topics)
+	}
+
+	for _, configs := range [][]TopicConfig{
+		{{Key: "weather"}},
+		{{Key: "Weather", Name: "Weather"}},
+		{{Key: "sports"}, {Key: "sports"}},
+		{{Key: "business"}},
+	} {
+		country.Topics = configs
+		if _, err = countries.TopicsFor(country); err == nil {
+			t.Errorf("expected %v to be rejected", configs)
+		}
+	}
+}
+
+func TestLanguageCodes(t *testing.T) {
+	countries, err := LoadCountries("countries.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, problem := range validateCountryLanguages() {
+		t.Error(problem)
+	}
+
+	// France and Germany also have English files, so they offer both languages.
+	expected := map[string][]uint8{
+		"Japan":       {0},
+		"Canada":      {1, 3},
+		"France":      {1, 3},
+		"Germany":     {1, 2},
+		"Switzerland": {2, 3, 5},
+	}
+
+	var switzerland []CountryConfig
+	for _, country := range countries.Countries {
+		if languages, ok := expected[country.Name]; ok && !slices.Equal(country.LanguageCodes(), languages) {
+			t.Errorf("%s (%s): expected %v, got %v", country.Name, country.Language, languages, country.LanguageCodes())
+		}
+		if country.CountryCode == 108 {
+			switzerland = append(switzerland, country)
+		}
+	}
+
+	// Every language of a country lists the same languages and asks which one to use.
+	for _, country := range switzerland {
+		n := News{currentCountryCode: 108, currentLanguageCode: country.LanguageCode, supportedLanguages: country.LanguageCodes()}
+		n.MakeHeader()
+
+		expected := [16]uint8{2, 3, 5, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+		if n.Header.SupportedLanguages != expected || n.Header.ShowLanguageSelectScreen != 1 || n.Header.LanguageCode != country.LanguageCode {
+			t.Errorf("%s: unexpected header %+v", country.Language, n.Header)
+		}
+	}
+	if len(switzerland) != 3 {
+		t.Errorf("expected 3 Swiss entries, got %d", len(switzerland))
+	}
+}
+
+func TestHiddenTopics(t *testing.T) {
+	data, articles := makeFakeNews(t, []TopicLabel{{news.Sports, "Deportes"}, {news.NationalNews, "Nacional"}})
+	file := newsFile{t, data}
+
+	var header Header
+	file.read(0, &header)
+
+	// Only the articles of the topics shown are written, and topics follow the configured order.
+	if len(articles) != 2 || articles[0].Topic != news.NationalNews || articles[1].Topic != news.Sports {
+		t.Fatalf("unexpected articles %v", articles)
+	}
+
+	topics := make([]Topic, header.NumberOfTopics)
+	file.read(header.TopicTableOffset, topics)
+	if len(topics) != 3 {
+		t.Fatalf("expected 2 topics, got %d", len(topics)-1)
+	}
+
+	for i, name := range []string{"Deportes", "Nacional"} {
+		topic := topics[i+1]
+		if text := file.terminatedText("topic", topic.TextOffset); text != name {
+			t.Errorf("topic %d: expected %q, got %q", i, name, text)
+		}
+
+		timestamps := make([]Timestamp, topic.NumberOfArticles)
+		file.read(topic.TimestampTableOffset, timestamps)
+		if len(timestamps) != 1 || timestamps[0].ArticleNumber != articleID(12, 1-i) {
+			t.Errorf("topic %d: unexpected timestamps %v", i, timestamps)
+		}
+	}
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"NewsChannel/store"
 	"bytes"
 	"fmt"
 	"hash/crc32"
 	"os"
+	"path/filepath"
 	"slices"
 	"testing"
 	"time"
@@ -12,7 +14,7 @@ import (
 	"github.com/wii-tools/lzx/lz10"
 )
 
-func makeNews(_t *testing.T, hour int, dayDelta int) {
+func makeNews(_t *testing.T, articleStore store.Store, hour int, dayDelta int) {
 	// Load countries from JSON file
 	countries, err := LoadCountries("countries.json")
 	if err != nil {
@@ -34,8 +36,14 @@ func makeNews(_t *testing.T, hour int, dayDelta int) {
 			continue
 		}
 		n := News{}
+		n.articleStore = articleStore
 		n.currentCountryCode = countryConfig.CountryCode
 		n.currentLanguageCode = countryConfig.LanguageCode
+		n.supportedLanguages = countryConfig.LanguageCodes()
+		n.topicLabels, err = countries.TopicsFor(countryConfig)
+		if err != nil {
+			_t.Fatal(err)
+		}
 
 		now := time.Now()
 		t := time.Date(now.Year(), now.Month(), now.Day()-dayDelta, hour, 0, 0, 0, time.Local)
@@ -43,9 +51,13 @@ func makeNews(_t *testing.T, hour int, dayDelta int) {
 		n.currentHour = t.Hour()
 
 		buffer := new(bytes.Buffer)
-		n.ReadNewsCache()
+		err = n.ReadNewsCache()
+		if err != nil {
+			_t.Fatal(err)
+		}
+
 		n.setSource(countryConfig.Source)
-		err := n.GetNewsArticles()
+		err = n.GetNewsArticles()
 		if err != nil {
 			_t.Fatal(err)
 		}
@@ -55,9 +67,12 @@ func makeNews(_t *testing.T, hour int, dayDelta int) {
 		n.MakeArticleTable()
 		n.MakeTopicTable()
 		n.MakeSourceTable()
-		n.WriteNewsCache()
 		n.MakeLocationTable()
 		n.WriteImages()
+		err = n.WriteNewsCache()
+		if err != nil {
+			_t.Fatal(err)
+		}
 		n.Header.Filesize = n.GetCurrentSize()
 		n.WriteAll(buffer)
 
@@ -91,11 +106,19 @@ func makeNews(_t *testing.T, hour int, dayDelta int) {
 func TestAllFileGeneration(_t *testing.T) {
 	t := time.Now()
 
+	articleStore, err := store.Open(filepath.Join(_t.TempDir(), "articles.db"))
+	if err != nil {
+		_t.Fatal(err)
+	}
+	defer func() {
+		_ = articleStore.Close()
+	}()
+
 	for i := 0; i < t.Hour(); i++ {
-		makeNews(_t, i, 0)
+		makeNews(_t, articleStore, i, 0)
 	}
 
 	for i := t.Hour(); i < 24; i++ {
-		makeNews(_t, i, 1)
+		makeNews(_t, articleStore, i, 1)
 	}
 }
@@ -4,16 +4,14 @@ go 1.25.0
 
 require (
 	github.com/PuerkitoBio/goquery v1.11.0
+	github.com/andybalholm/cascadia v1.3.3
 	github.com/getsentry/sentry-go v0.42.0
 	github.com/logrusorgru/aurora/v4 v4.0.0
-	github.com/pmezard/go-difflib v1.0.0
 	github.com/wii-tools/lzx v0.0.0-20231115152519-4c1183c96cc6
+	go.etcd.io/bbolt v1.4.3
 	golang.org/x/image v0.38.0
+	golang.org/x/net v0.49.0
+	golang.org/x/text v0.35.0
 )
 
-require (
-	github.com/andybalholm/cascadia v1.3.3 // indirect
-	golang.org/x/net v0.49.0 // indirect
-	golang.org/x/sys v0.40.0 // indirect
-	golang.org/x/text v0.35.0 // indirect
-)
+require golang.org/x/sys v0.40.0 // indirect
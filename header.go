@@ -33,7 +33,20 @@ type Header struct {
 	HeadlinesTableOffset     uint32
 }
 
+// MakeHeader writes the header, listing the languages the country's files are made in. The console only asks which
+// language to use if there is more than one.
 func (n *News) MakeHeader() {
+	var supportedLanguages [16]uint8
+	for i := range supportedLanguages {
+		supportedLanguages[i] = 0xFF
+	}
+	copy(supportedLanguages[:], n.supportedLanguages)
+
+	var showLanguageSelectScreen uint8
+	if len(n.supportedLanguages) > 1 {
+		showLanguageSelectScreen = 1
+	}
+
 	n.Header = Header{
 		Version:                  512,
 		Filesize:                 0,
@@ -42,10 +55,10 @@ func (n *News) MakeHeader() {
 		EndTimestamp:             fixTime(currentTime) + 1500,
 		CountryCode:              n.currentCountryCode,
 		UpdatedTimestamp2:        fixTime(currentTime),
-		SupportedLanguages:       [16]uint8{1, 3, 4, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+		SupportedLanguages:       supportedLanguages,
 		LanguageCode:             n.currentLanguageCode,
 		GooFlag:                  0,
-		ShowLanguageSelectScreen: 0,
+		ShowLanguageSelectScreen: showLanguageSelectScreen,
 		DownloadInterval:         30,
 		MessageOffset:            0,
 		NumberOfTopics:           0,
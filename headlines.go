@@ -1,6 +1,9 @@
 package main
 
-import "unicode/utf16"
+import (
+	"NewsChannel/news"
+	"unicode/utf16"
+)
 
 // Headlines are the news articles that will appear on the News Channel banner in the Wii Menu.
 type Headlines struct {
@@ -21,8 +24,12 @@ func (n *News) MakeWiiMenuHeadlines() {
 	for i := 0; i < numberOfHeadlines; i++ {
 		article := n.articles[i]
 
+		// The banner has less room than the article list.
+		title, truncated := news.Truncate(article.Title, news.Limits.BannerHeadline, n.GetLanguageTag())
+		n.recordTruncation(news.FieldBannerHeadline, truncated)
+
 		// Encode to UTF-16
-		encoded := utf16.Encode([]rune(article.Title))
+		encoded := utf16.Encode([]rune(title))
 
 		n.Headlines[i] = Headlines{
 			HeadlineSize:   uint32(len(encoded)) * 2,
@@ -1,8 +1,13 @@
 package main
 
 import (
+	"NewsChannel/news"
 	"math"
+	"strings"
+	"unicode"
 	"unicode/utf16"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 type Location struct {
@@ -16,17 +21,68 @@ type Location struct {
 	_            [3]byte
 }
 
-const float64EqualityThreshold = 1e-9
+// coordinateUnit is the size in degrees of a single unit in the encoded coordinates.
+const coordinateUnit = 0.0054931640625
 
-func floatCompare(a, b float64) bool {
-	return math.Abs(a-b) <= float64EqualityThreshold
-}
+// locationMergeDistance is the distance in kilometres under which two locations with the same name share a pin.
+const locationMergeDistance = 50.0
+
+const earthRadius = 6371.0
 
 func CoordinateEncode(value float64) int16 {
-	value /= 0.0054931640625
+	value /= coordinateUnit
 	return int16(value)
 }
 
+func coordinateDecode(value int16) float64 {
+	return float64(value) * coordinateUnit
+}
+
+// isSameLocation reports whether two locations should be shown as a single pin on the globe.
+// The same place can come from the common locations and from Nominatim with slightly different coordinates,
+// so we compare what the console will actually see rather than the raw coordinates.
+func isSameLocation(a, b *news.Location) bool {
+	aLatitude, aLongitude := CoordinateEncode(a.Latitude), CoordinateEncode(a.Longitude)
+	bLatitude, bLongitude := CoordinateEncode(b.Latitude), CoordinateEncode(b.Longitude)
+	if aLatitude == bLatitude && aLongitude == bLongitude {
+		return true
+	}
+
+	if normalizeLocationName(a.Name) != normalizeLocationName(b.Name) {
+		return false
+	}
+
+	return distance(aLatitude, aLongitude, bLatitude, bLongitude) <= locationMergeDistance
+}
+
+// distance returns the great-circle distance in kilometres between two encoded coordinates.
+func distance(aLatitude, aLongitude, bLatitude, bLongitude int16) float64 {
+	lat1 := coordinateDecode(aLatitude) * math.Pi / 180
+	lat2 := coordinateDecode(bLatitude) * math.Pi / 180
+	deltaLat := lat2 - lat1
+	deltaLon := (coordinateDecode(bLongitude) - coordinateDecode(aLongitude)) * math.Pi / 180
+
+	h := math.Pow(math.Sin(deltaLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(deltaLon/2), 2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(math.Min(h, 1)))
+}
+
+// normalizeLocationName folds case, accents and punctuation so "St. Louis" and "st louis" compare equal.
+func normalizeLocationName(name string) string {
+	var builder strings.Builder
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			builder.WriteRune(unicode.ToLower(r))
+		case unicode.IsSpace(r) || r == '-':
+			builder.WriteRune(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
 func (n *News) MakeLocationTable() {
 	n.Header.LocationTableOffset = n.GetCurrentSize()
 
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"NewsChannel/news"
+	"testing"
+)
+
+func TestIsSameLocation(t *testing.T) {
+	common := &news.Location{Latitude: 35.689487, Longitude: 139.691711, Name: "Tokyo"}
+
+	tests := []struct {
+		name     string
+		location *news.Location
+		expected bool
+	}{
+		{"nominatim result for the same place", &news.Location{Latitude: 35.6768601, Longitude: 139.7638947, Name: "Tokyo"}, true},
+		{"different spelling of the name", &news.Location{Latitude: 35.7, Longitude: 139.7, Name: " TOKYO "}, true},
+		{"same quantized coordinates", &news.Location{Latitude: 35.6895, Longitude: 139.6917, Name: "Shinjuku"}, true},
+		{"nearby but different place", &news.Location{Latitude: 35.443707, Longitude: 139.638031, Name: "Yokohama"}, false},
+		{"same name far away", &news.Location{Latitude: 33.3, Longitude: 131.5, Name: "Tokyo"}, false},
+	}
+
+	for _, test := range tests {
+		if actual := isSameLocation(common, test.location); actual != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestNormalizeLocationName(t *testing.T) {
+	if normalizeLocationName("St. Louis") != normalizeLocationName("st louis") {
+		t.Error("punctuation and case should be ignored")
+	}
+	if normalizeLocationName("Córdoba") != "cordoba" {
+		t.Errorf("accents should be removed, got %q", normalizeLocationName("Córdoba"))
+	}
+}
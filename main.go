@@ -2,6 +2,7 @@ package main
 
 import (
 	"NewsChannel/news"
+	"NewsChannel/store"
 	"bytes"
 	"encoding/binary"
 	"encoding/xml"
@@ -34,15 +35,28 @@ type News struct {
 	Images          []Image
 	ImagesData      []byte
 	CaptionData     []uint16
+	CreditData      []uint16
 
-	source news.Source
+	source       news.Source
+	articleStore store.Store
 
 	currentLanguageCode uint8
 	currentCountryCode  uint8
 	currentHour         int
+	countryName         string
+	sourceName          string
 
-	// Titles of articles from previous hours. Required for making sure we don't have duplicates.
-	oldArticleTitles []string
+	// Topics preferred when the same story was found under several.
+	topicPriority []news.Topic
+
+	// Topics shown in the channel, in the order of the topic table.
+	topicLabels []TopicLabel
+
+	// Languages the country's files are made in, which the console lets the user choose from.
+	supportedLanguages []uint8
+
+	// Articles from previous hours. Required for making sure we don't have duplicates.
+	dedup *news.Deduplicator
 
 	// Placeholder for the timestamps for a specific topic.
 	timestamps [][]Timestamp
@@ -57,23 +71,75 @@ type News struct {
 }
 
 type Config struct {
-	XMLName       xml.Name `xml:"Config"`
-	RSSHubAddress string   `xml:"RSSHubAddress"`
-	SentryDSN     string   `xml:"SentryDSN"`
-	IsDebug       bool     `xml:"IsDebug"`
+	XMLName          xml.Name `xml:"Config"`
+	RSSHubAddress    string   `xml:"RSSHubAddress"`
+	SentryDSN        string   `xml:"SentryDSN"`
+	IsDebug          bool     `xml:"IsDebug"`
+	LocationDataPath string   `xml:"LocationDataPath"`
+	// A directory of <source>.json files replacing the built-in rules that remove boilerplate from articles.
+	BoilerplatePath string `xml:"BoilerplatePath"`
+	// Pick the same topic for a story in every country sharing a language.
+	ClusterAcrossCountries bool `xml:"ClusterAcrossCountries"`
+	// Add skipped articles as Sentry breadcrumbs, in addition to the skip report.
+	SkipBreadcrumbs bool `xml:"SkipBreadcrumbs"`
+	// How article pictures are converted. Settings that are left out keep their default.
+	ImagePipeline news.ImagePipeline `xml:"ImagePipeline"`
+	// Where downloaded and converted pictures are kept between runs, and how many megabytes they may take.
+	// A size of zero disables the cache.
+	ImageCachePath string `xml:"ImageCachePath"`
+	ImageCacheSize int64  `xml:"ImageCacheSize"`
+	// How many gallery pictures are kept to fall back on, and how many kilobytes of pictures a news file may hold.
+	// The console shows one picture per article, so a fallback is only downloaded and used if the pictures before it
+	// can't be converted or don't fit the budget. A budget of zero removes the limit.
+	PictureFallbacks int `xml:"PictureFallbacks"`
+	ImageBudget      int `xml:"ImageBudget"`
+	// How long headlines, bodies, captions and location names may be. Limits that are left out keep their default.
+	TextLimits news.TextLimits `xml:"TextLimits"`
+	// Whether articles from feeds covering several topics are classified, and how confident the classifier must be.
+	// A directory of <language>.json files replaces the built-in training data.
+	TopicClassification news.TopicClassification `xml:"TopicClassification"`
+	TopicDataPath       string                   `xml:"TopicDataPath"`
+	// A directory of <source>.json files listing feeds read besides the built-in ones and the topics they fill.
+	FeedsPath string `xml:"FeedsPath"`
+	// Which sources and topics have long articles summarized, and to how many UTF-16 units.
+	Summaries []news.SummaryRule `xml:"Summaries>Summary"`
 }
 
 var currentTime = 0
 
+// The most bytes of pictures written into a news file. Zero if there is no limit.
+var imageBudget = 0
+
+// How often the texts of each source were truncated during this run. Nil if they aren't counted.
+var truncations *TruncationReport
+
+// Stories kept by the countries processed so far, per language. Nil if countries aren't clustered together.
+var sharedStories map[string]*news.SharedStories
+
+const articleStorePath = "./cache/articles.db"
+
 func main() {
 	// Load config
 	rawConfig, err := os.ReadFile("./config.xml")
 	checkError(err)
 
-	config := &Config{}
+	config := &Config{
+		ImagePipeline:       news.DefaultImagePipeline,
+		ImageCachePath:      "./cache/images",
+		ImageCacheSize:      64,
+		PictureFallbacks:    news.PictureFallbacks,
+		ImageBudget:         512,
+		TextLimits:          news.DefaultTextLimits,
+		TopicClassification: news.DefaultTopicClassification,
+	}
 	err = xml.Unmarshal(rawConfig, config)
 	checkError(err)
 
+	// Validating the editable data files doesn't require generating anything.
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidation(config))
+	}
+
 	// Before we do anything, init Sentry to capture all errors.
 	err = sentry.Init(sentry.ClientOptions{
 		Dsn:   config.SentryDSN,
@@ -84,12 +150,83 @@ func main() {
 
 	news.RSSHubAddress = config.RSSHubAddress
 
+	err = config.ImagePipeline.Validate()
+	checkError(err)
+	news.Images = config.ImagePipeline
+
+	if config.PictureFallbacks < 0 {
+		checkError(errors.New("PictureFallbacks can't be negative"))
+	}
+	news.PictureFallbacks = config.PictureFallbacks
+
+	if config.ImageBudget < 0 {
+		checkError(errors.New("ImageBudget can't be negative"))
+	}
+	imageBudget = config.ImageBudget * 1024
+
+	err = config.TextLimits.Validate()
+	checkError(err)
+	news.Limits = config.TextLimits
+
+	// Summary rules are checked against the topics of the feeds.
+	err = news.LoadFeeds(config.FeedsPath)
+	checkError(err)
+
+	for _, rule := range config.Summaries {
+		checkError(rule.Validate())
+	}
+	news.Summaries = config.Summaries
+
+	err = news.LoadLocations(config.LocationDataPath)
+	checkError(err)
+
+	err = news.LoadBoilerplate(config.BoilerplatePath)
+	checkError(err)
+
+	err = config.TopicClassification.Validate()
+	checkError(err)
+	news.Classification = config.TopicClassification
+
+	err = news.LoadTopicData(config.TopicDataPath)
+	checkError(err)
+
 	// Load countries from JSON file
 	countries, err := LoadCountries("countries.json")
 	checkError(err)
 
+	err = os.MkdirAll("./cache", os.ModePerm)
+	if !os.IsExist(err) {
+		checkError(err)
+	}
+
+	articleStore, err := store.Open(articleStorePath)
+	checkError(err)
+	defer func(articleStore store.Store) {
+		err := articleStore.Close()
+		if err != nil {
+			log.Println("error closing article store:", err)
+		}
+	}(articleStore)
+
+	if config.ImageCacheSize > 0 {
+		news.PictureCache, err = news.NewImageCache(config.ImageCachePath, config.ImageCacheSize*1024*1024)
+		checkError(err)
+	}
+
+	// Move articles from the cache files of older versions into the store.
+	err = migrateNewsCache(articleStore)
+	checkError(err)
+
+	if config.ClusterAcrossCountries {
+		sharedStories = make(map[string]*news.SharedStories)
+	}
+
+	report := &SkipReport{GeneratedAt: time.Now()}
+	truncations = &TruncationReport{GeneratedAt: report.GeneratedAt}
+
 	// Process each country/language combination
 	for _, countryConfig := range countries.Countries {
+		news.SkipHandler = report.collect(countryConfig, config.SkipBreadcrumbs)
 		func(countryConfig CountryConfig) {
 			defer func() {
 				if r := recover(); r != nil {
@@ -99,15 +236,36 @@ func main() {
 					ReportError(errors.New(errorString))
 				}
 			}()
-			processNews(countryConfig)
+			processNews(countryConfig, countries, articleStore)
 		}(countryConfig)
 	}
+
+	err = report.write(reportDir)
+	if err != nil {
+		ReportError(err)
+	}
+
+	truncations.log()
+	err = truncations.write(reportDir)
+	if err != nil {
+		ReportError(err)
+	}
+
+	if news.PictureCache != nil {
+		err = news.PictureCache.Prune()
+		if err != nil {
+			ReportError(err)
+		}
+	}
 }
 
-func processNews(countryConfig CountryConfig) {
+func processNews(countryConfig CountryConfig, countries *Countries, articleStore store.Store) {
 	n := News{}
+	n.articleStore = articleStore
 	n.currentCountryCode = countryConfig.CountryCode
 	n.currentLanguageCode = countryConfig.LanguageCode
+	n.countryName = countryConfig.Name
+	n.supportedLanguages = countryConfig.LanguageCodes()
 
 	log.Printf("Processing %s (%s) - Country: %d, Language: %d",
 		countryConfig.Name, countryConfig.Language,
@@ -118,9 +276,31 @@ func processNews(countryConfig CountryConfig) {
 	n.currentHour = t.Hour()
 
 	buffer := new(bytes.Buffer)
-	n.ReadNewsCache()
+	for _, key := range countryConfig.TopicPriority {
+		topic, err := news.ParseTopic(key)
+		if err != nil {
+			ReportError(fmt.Errorf("%s: %w", countryConfig.Name, err))
+			return
+		}
+
+		n.topicPriority = append(n.topicPriority, topic)
+	}
+
+	var err error
+	n.topicLabels, err = countries.TopicsFor(countryConfig)
+	if err != nil {
+		ReportError(fmt.Errorf("%s: %w", countryConfig.Name, err))
+		return
+	}
+
+	err = n.ReadNewsCache()
+	if err != nil {
+		ReportError(err)
+		return
+	}
+
 	n.setSource(countryConfig.Source)
-	err := n.GetNewsArticles()
+	err = n.GetNewsArticles()
 	if err != nil {
 		ReportError(err)
 		return
@@ -130,9 +310,14 @@ func processNews(countryConfig CountryConfig) {
 	n.MakeArticleTable()
 	n.MakeTopicTable()
 	n.MakeSourceTable()
-	n.WriteNewsCache()
 	n.MakeLocationTable()
 	n.WriteImages()
+	// The pictures are chosen by now, so the store refers to the ones in the file.
+	err = n.WriteNewsCache()
+	if err != nil {
+		// The file can still be served, it just won't be listed in the following hours.
+		ReportError(err)
+	}
 	n.Header.Filesize = n.GetCurrentSize()
 	n.WriteAll(buffer)
 
@@ -186,6 +371,7 @@ func (n *News) WriteAll(writer io.Writer) {
 	Write(writer, n.Images)
 	Write(writer, n.ImagesData)
 	Write(writer, n.CaptionData)
+	Write(writer, n.CreditData)
 }
 
 func (n *News) GetCurrentSize() uint32 {
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"NewsChannel/news"
+	"NewsChannel/store"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// NewsCache is an article in the hourly cache files used before the article store.
+type NewsCache struct {
+	ID        uint32     `json:"id"`
+	Timestamp uint32     `json:"timestamp"`
+	Topic     news.Topic `json:"topic"`
+	Title     string     `json:"title"`
+}
+
+// NewsCacheFile is the contents of a version 2 cache file.
+// Version 1 files are a bare array whose IDs were numbered from 1 in every hour.
+type NewsCacheFile struct {
+	Version  int         `json:"version"`
+	Articles []NewsCache `json:"articles"`
+}
+
+// migrateNewsCache moves the articles of every hourly cache file into the article store.
+// Migrated files are renamed so that they are only migrated once.
+func migrateNewsCache(articleStore store.Store) error {
+	paths, err := filepath.Glob("./cache/cache_*_*_*.news")
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		var hour int
+		var key store.Key
+		_, err = fmt.Sscanf(filepath.Base(path), "cache_%d_%d_%d.news", &hour, &key.CountryCode, &key.LanguageCode)
+		if err != nil {
+			return fmt.Errorf("%s: unexpected cache file name: %w", path, err)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		// The file name only contains the hour, the day is the one it was last written on.
+		modified := info.ModTime()
+		generatedAt := time.Date(modified.Year(), modified.Month(), modified.Day(), hour, 0, 0, 0, time.Local)
+		if generatedAt.After(modified) {
+			generatedAt = generatedAt.AddDate(0, 0, -1)
+		}
+
+		cache, err := readNewsCacheFile(path, hour)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		var articles []store.Article
+		for _, article := range cache {
+			articles = append(articles, store.Article{
+				ID:        article.ID,
+				Timestamp: article.Timestamp,
+				Article: news.Article{
+					Title: article.Title,
+					Topic: article.Topic,
+				},
+			})
+		}
+
+		err = articleStore.PutHour(key, generatedAt, articles)
+		if err != nil {
+			return err
+		}
+
+		err = os.Rename(path, path+".migrated")
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Migrated %d articles from %s", len(articles), path)
+	}
+
+	return nil
+}
+
+// readNewsCacheFile reads the articles of a cache file generated at the given hour.
+func readNewsCacheFile(path string, hour int) ([]NewsCache, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > 0 && data[0] == '[' {
+		var articles []NewsCache
+		err = json.Unmarshal(data, &articles)
+		if err != nil {
+			return nil, err
+		}
+
+		// Move the IDs into the range of the hour they were generated in.
+		for i := range articles {
+			articles[i].ID = articleID(hour, int(articles[i].ID)-1)
+		}
+
+		return articles, nil
+	}
+
+	var file NewsCacheFile
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.Version != 2 {
+		return nil, fmt.Errorf("unsupported news cache version %d", file.Version)
+	}
+
+	return file.Articles, nil
+}
@@ -49,6 +49,15 @@ func (a *ANSA) GetArticles() ([]news.Article, error) {
 	}
 	articles = append(articles, temp...)
 
+	// Additional feeds from the feeds data files.
+	for _, feed := range news.FeedsFor("ansa") {
+		temp, err = a.getArticles(feed.URL, feed.Topic)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, temp...)
+	}
+
 	return articles, nil
 }
 
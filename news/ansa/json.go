@@ -3,13 +3,18 @@ package ansa
 import (
 	"NewsChannel/news"
 	"encoding/xml"
+	stdhtml "html"
 	"log"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
+// ANSA only serves browsers.
+const userAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"
+
 // RSS structures for parsing ANSA XML feeds
 type RSS struct {
 	XMLName xml.Name `xml:"rss"`
@@ -33,7 +38,7 @@ type Item struct {
 
 func (a *ANSA) getArticles(url string, topic news.Topic) ([]news.Article, error) {
 	// Fetch RSS XML
-	data, err := news.HttpGet(url, "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+	data, err := news.HttpGet(url, userAgent)
 	if err != nil {
 		return nil, err
 	}
@@ -53,33 +58,51 @@ func (a *ANSA) getArticles(url string, topic news.Topic) ([]news.Article, error)
 
 		title := news.SanitizeText(item.Title)
 		// Check for duplicates
-		if news.IsDuplicateArticle(a.oldArticleTitles, title) {
+		skip := news.SkipEvent{
+			Source: "ansa",
+			Topic:  topic.String(),
+			URL:    item.Link,
+			Title:  title,
+		}
+		if match, duplicate := a.dedup.Find(title, news.SanitizeText(item.Description)); duplicate {
+			skip.Reason, skip.MatchedTitle, skip.Score = news.SkipDuplicate, match.Title, match.Score
+			news.ReportSkip(skip)
 			continue
 		}
-		a.oldArticleTitles = append(a.oldArticleTitles, title)
 
 		// Get full article content by scraping the link
-		content, location, thumbnail := a.getFullArticle(item.Link)
+		body, location, html := a.getFullArticle(item.Link)
 
 		// Use description as fallback if content fetch fails
-		if content == "" {
-			content = item.Description
+		if len(body) == 0 {
+			body = news.BoilerplateFor("ansa").ParseBodyHTML(item.Description)
 		}
-
-		// Clean HTML tags from content
-		content = news.SanitizeText(content)
+		content := body.Render()
 
 		// Skip if no content
-		if len(strings.TrimSpace(content)) == 0 {
+		if len(content) == 0 {
+			skip.Reason = news.SkipNoContent
+			news.ReportSkip(skip)
 			continue
 		}
+		a.dedup.Add(title, news.Lead(content))
+
+		// Pictures are only fetched once we know the article is used.
+		var pictures []news.Thumbnail
+		if html != "" {
+			pictures = a.extractPictures(html, item.Link)
+		}
 
 		article := news.Article{
-			Title:     title,
-			Content:   &content,
-			Topic:     topic,
-			Location:  location,
-			Thumbnail: thumbnail,
+			Title:         title,
+			Source:        skip.Source,
+			URL:           skip.URL,
+			Content:       &content,
+			Body:          body,
+			Topic:         topic,
+			Location:      location,
+			Pictures:      pictures,
+			PublishedTime: news.ParseTime(item.PubDate, time.RFC1123Z, time.RFC1123),
 		}
 
 		articles = append(articles, article)
@@ -88,55 +111,50 @@ func (a *ANSA) getArticles(url string, topic news.Topic) ([]news.Article, error)
 	return articles, nil
 }
 
-func (a *ANSA) getFullArticle(articleURL string) (content string, location *news.Location, thumbnail *news.Thumbnail) {
+// getFullArticle returns the content and location of an article, along with its page to find the thumbnail in.
+func (a *ANSA) getFullArticle(articleURL string) (body news.Body, location *news.Location, html string) {
 	if articleURL == "" {
-		return "", nil, nil
+		return nil, nil, ""
 	}
 
-	data, err := news.HttpGet(articleURL, "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+	data, err := news.HttpGet(articleURL, userAgent)
 	if err != nil {
 		log.Printf("Failed to fetch article content from %s: %v", articleURL, err)
-		return "", nil, nil
+		return nil, nil, ""
 	}
 
-	html := string(data)
+	html = string(data)
 
-	content = a.extractArticleBody(html)
+	body = a.extractArticleBody(html)
 	location = a.extractLocationFromTags(html)
-	thumbnail = a.extractThumbnail(html)
 
-	return content, location, thumbnail
+	return body, location, html
 }
 
-func (a *ANSA) extractArticleBody(html string) string {
+func (a *ANSA) extractArticleBody(html string) news.Body {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		log.Println("Failed to parse HTML:", err)
-		return ""
+		return nil
 	}
 
-	var builder strings.Builder
-
 	// Select the main article body div
-	doc.Find(`div.post-single-text.rich-text.news-txt[itemprop="articleBody"]`).Each(func(i int, section *goquery.Selection) {
-		section.Find(`div.rich-text, #piano-container`).Remove()
-
-		section.Find("p, div, span, h1, h2, h3, h4, h5, h6").Each(func(j int, elem *goquery.Selection) {
-			// Split on tabs (ANSA add a tab at the start of each paragraph)
-			paragraphSplit := strings.Split(elem.Text(), "\t")
-
-			for _, paragraph := range paragraphSplit {
-				text := strings.TrimSpace(paragraph)
-				text = strings.ReplaceAll(text, "\n", " ")
-				if text != "" && !strings.Contains(text, "Copyright ANSA") {
-					builder.WriteString(text + "\n\n")
-				}
-			}
-		})
+	section := doc.Find(`div.post-single-text.rich-text.news-txt[itemprop="articleBody"]`)
+
+	// ANSA add a tab at the start of each paragraph rather than putting them in elements.
+	section.Find("*").AddSelection(section).Contents().Each(func(i int, node *goquery.Selection) {
+		if goquery.NodeName(node) != "#text" || !strings.Contains(node.Text(), "\t") {
+			return
+		}
+
+		paragraphs := strings.Split(node.Text(), "\t")
+		for j, paragraph := range paragraphs {
+			paragraphs[j] = stdhtml.EscapeString(paragraph)
+		}
+		node.ReplaceWithHtml(strings.Join(paragraphs, "<br>"))
 	})
 
-	result := strings.TrimSpace(builder.String())
-	return result
+	return news.BoilerplateFor("ansa").ParseBody(section)
 }
 
 func (a *ANSA) extractLocationFromTags(html string) *news.Location {
@@ -179,29 +197,15 @@ func (a *ANSA) extractLocationFromTags(html string) *news.Location {
 	return news.GetLocationForExtractedLocation(tags, "it")
 }
 
-func (a *ANSA) extractThumbnail(html string) *news.Thumbnail {
+func (a *ANSA) extractPictures(html string, articleURL string) []news.Thumbnail {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		log.Println("Failed to parse HTML:", err)
 		return nil
 	}
 
-	var imageURL string
-
-	doc.Find(`meta[property="og:image"]`).EachWithBreak(func(i int, s *goquery.Selection) bool {
-		if content, exists := s.Attr("content"); exists && strings.TrimSpace(content) != "" {
-			imageURL = content
-			return false
-		}
-		return true
-	})
-
-	if imageURL == "" {
-		return nil
-	}
-
-	imageData, err := news.HttpGet(imageURL, "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-	if err != nil || len(imageData) == 0 {
+	image, imageURL := news.ConvertFirstCandidate(news.ImageCandidates(doc, articleURL), userAgent)
+	if image == nil {
 		return nil
 	}
 
@@ -222,8 +226,13 @@ func (a *ANSA) extractThumbnail(html string) *news.Thumbnail {
 		})
 	}
 
-	return &news.Thumbnail{
-		Image:   news.ConvertImage(imageData),
-		Caption: news.SanitizeText(caption),
-	}
+	// The credit has its own element in the figure, or is part of the caption.
+	caption, credit := news.CaptionCredit(caption, news.FigureCredit(doc.Find("figure.image").First()))
+
+	pictures := []news.Thumbnail{{
+		Image:   image,
+		Caption: caption,
+		Credit:  credit,
+	}}
+	return append(pictures, news.GalleryFallbacks(doc, articleURL, imageURL, news.PictureFallbacks, userAgent)...)
 }
@@ -0,0 +1,26 @@
+package ansa
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestExtractArticleBodyBoilerplate extracts the saved article body through the same path as the live articles,
+// so rules that only match an ancestor of the selected elements are exercised too.
+func TestExtractArticleBodyBoilerplate(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join("..", "testdata", "boilerplate", "ansa.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := os.ReadFile(filepath.Join("..", "testdata", "boilerplate", "ansa.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := (&ANSA{}).extractArticleBody(string(page))
+	if actual := body.Render(); actual != strings.TrimSpace(string(expected)) {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
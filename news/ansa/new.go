@@ -1,6 +1,7 @@
 package ansa
 
 import (
+	"NewsChannel/news"
 	_ "embed"
 	"fmt"
 	"strconv"
@@ -9,15 +10,15 @@ import (
 )
 
 type ANSA struct {
-	oldArticleTitles []string
+	dedup *news.Deduplicator
 }
 
 //go:embed logo.jpg
 var Logo []byte
 
-func NewAnsa(oldArticleTitles []string) *ANSA {
+func NewAnsa(dedup *news.Deduplicator) *ANSA {
 	return &ANSA{
-		oldArticleTitles: oldArticleTitles,
+		dedup: dedup,
 	}
 }
 
@@ -50,6 +50,15 @@ func (a *AP) GetArticles() ([]news.Article, error) {
 	}
 	articles = append(articles, temp...)
 
+	// Additional feeds from the feeds data files.
+	for _, feed := range news.FeedsFor("ap") {
+		temp, err = a.getArticles(feed.URL, feed.Topic)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, temp...)
+	}
+
 	return articles, nil
 }
 
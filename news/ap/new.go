@@ -1,6 +1,7 @@
 package ap
 
 import (
+	"NewsChannel/news"
 	_ "embed"
 	"fmt"
 	"strconv"
@@ -9,15 +10,15 @@ import (
 )
 
 type AP struct {
-	oldArticleTitles []string
+	dedup *news.Deduplicator
 }
 
 //go:embed logo.jpg
 var Logo []byte
 
-func NewAP(oldArticleTitles []string) *AP {
+func NewAP(dedup *news.Deduplicator) *AP {
 	return &AP{
-		oldArticleTitles: oldArticleTitles,
+		dedup: dedup,
 	}
 }
 
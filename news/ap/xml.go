@@ -7,6 +7,7 @@ import (
 	"log"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -25,10 +26,29 @@ type Channel struct {
 }
 
 type Item struct {
-	Title       string `xml:"title"`
-	Description string `xml:"description"`
-	Link        string `xml:"link"`
-	GUID        string `xml:"guid"`
+	Title       string  `xml:"title"`
+	Description string  `xml:"description"`
+	Link        string  `xml:"link"`
+	PubDate     string  `xml:"pubDate"`
+	GUID        string  `xml:"guid"`
+	Media       []Media `xml:"http://search.yahoo.com/mrss/ content"`
+}
+
+// Media is a picture of an item, credited to its photographer and agency.
+type Media struct {
+	URL    string `xml:"url,attr"`
+	Credit string `xml:"http://search.yahoo.com/mrss/ credit"`
+}
+
+// credit returns the credit of the first of the item's pictures that has one, which is normally its lead picture.
+func (i Item) credit() string {
+	for _, media := range i.Media {
+		if media.Credit != "" {
+			return media.Credit
+		}
+	}
+
+	return ""
 }
 
 func (a *AP) getArticles(url string, topic news.Topic) ([]news.Article, error) {
@@ -49,27 +69,44 @@ func (a *AP) getArticles(url string, topic news.Topic) ([]news.Article, error) {
 	for _, item := range rss.Channel.Items {
 		title := news.SanitizeText(item.Title)
 		// Check for duplicates
-		if news.IsDuplicateArticle(a.oldArticleTitles, title) {
+		skip := news.SkipEvent{
+			Source: "ap",
+			Topic:  topic.String(),
+			URL:    item.Link,
+			Title:  title,
+		}
+		// The description is the start of the article, so its lead can be compared before the page is fetched.
+		lead := news.Lead(news.ParseBodyHTML(item.Description).Render())
+		if match, duplicate := a.dedup.Find(title, lead); duplicate {
+			skip.Reason, skip.MatchedTitle, skip.Score = news.SkipDuplicate, match.Title, match.Score
+			news.ReportSkip(skip)
 			continue
 		}
-		a.oldArticleTitles = append(a.oldArticleTitles, title)
 
 		// Get full article content by scraping the link
-		content, location, thumbnail, err := a.getFullArticle(item.Link)
+		body, location, pictures, err := a.getFullArticle(item.Link, item.credit())
 		if err != nil {
 			return nil, err
 		}
 
+		content := body.Render()
 		if content == "" {
+			skip.Reason = news.SkipNoContent
+			news.ReportSkip(skip)
 			continue
 		}
+		a.dedup.Add(title, news.Lead(content))
 
 		article := news.Article{
-			Title:     title,
-			Content:   &content,
-			Topic:     topic,
-			Location:  location,
-			Thumbnail: thumbnail,
+			Title:         title,
+			Source:        skip.Source,
+			URL:           skip.URL,
+			Content:       &content,
+			Body:          body,
+			Topic:         topic,
+			Location:      location,
+			Pictures:      pictures,
+			PublishedTime: news.ParseTime(item.PubDate, time.RFC1123Z, time.RFC1123),
 		}
 
 		articles = append(articles, article)
@@ -79,24 +116,24 @@ func (a *AP) getArticles(url string, topic news.Topic) ([]news.Article, error) {
 	return articles, nil
 }
 
-func (a *AP) getFullArticle(articleURL string) (string, *news.Location, *news.Thumbnail, error) {
+func (a *AP) getFullArticle(articleURL string, credit string) (news.Body, *news.Location, []news.Thumbnail, error) {
 	if articleURL == "" {
-		return "", nil, nil, errors.New("empty articleURL")
+		return nil, nil, nil, errors.New("empty articleURL")
 	}
 
 	data, err := news.HttpGet(articleURL)
 	if err != nil {
-		return "", nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	html := string(data)
 
-	content, locationString, err := a.extractArticleBody(html)
+	body, locationString, err := a.extractArticleBody(html)
 	if err != nil {
-		return "", nil, nil, err
+		return nil, nil, nil, err
 	}
-	if len(content) == 0 {
-		return "", nil, nil, nil
+	if len(body) == 0 {
+		return nil, nil, nil, nil
 	}
 
 	var location *news.Location
@@ -104,78 +141,52 @@ func (a *AP) getFullArticle(articleURL string) (string, *news.Location, *news.Th
 		location = news.GetLocationForExtractedLocation([]string{*locationString}, "en")
 	}
 
-	thumbnail := a.extractThumbnail(html)
+	pictures := a.extractPictures(html, articleURL, credit)
 
-	return strings.TrimSpace(content), location, thumbnail, nil
+	return body, location, pictures, nil
 }
 
-func (a *AP) extractArticleBody(html string) (string, *string, error) {
+func (a *AP) extractArticleBody(html string) (news.Body, *string, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 
-	var contentSlice []string
-
 	// Select the main article body div
-	doc.Find(`div.RichTextStoryBody`).Each(func(i int, section *goquery.Selection) {
-		section.Find("p").EachWithBreak(func(j int, elem *goquery.Selection) bool {
-			text := strings.TrimSpace(elem.Text())
-			if text == "___" {
-				// We have reached the article footer
-				return false
-			}
-
-			if text != "" {
-				contentSlice = append(contentSlice, text)
-			}
-			return true
-		})
-	})
-
-	if len(contentSlice) == 0 {
-		return "", nil, nil
+	body := news.BoilerplateFor("ap").ParseBody(doc.Find(`div.RichTextStoryBody`).Find("p, h2, h3, ul"))
+	for i, block := range body {
+		if block.Text == "___" {
+			// We have reached the article footer
+			body = body[:i]
+			break
+		}
 	}
 
-	var content string
-	for _, paragraph := range contentSlice {
-		content += news.SanitizeText(paragraph)
-		content += "\n\n"
+	if len(body) == 0 {
+		return nil, nil, nil
 	}
 
 	// Get the location
 	locationRegex := regexp.MustCompile(`(.*?) \(AP\) — `)
-	location := locationRegex.FindStringSubmatch(contentSlice[0])
+	location := locationRegex.FindStringSubmatch(body[0].Text)
 	if len(location) > 1 && len(location[1]) > 0 {
-		return content, &location[1], nil
+		return body, &location[1], nil
 	}
 
-	return content, nil, nil
+	return body, nil, nil
 }
 
-func (a *AP) extractThumbnail(html string) *news.Thumbnail {
+// extractPictures returns the pictures of an article page. credit is the credit the feed gives the lead picture.
+func (a *AP) extractPictures(html string, articleURL string, credit string) []news.Thumbnail {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		log.Println("Failed to parse HTML:", err)
 		return nil
 	}
 
-	var imageURL string
-
-	doc.Find(`meta[property="og:image"]`).EachWithBreak(func(i int, s *goquery.Selection) bool {
-		if content, exists := s.Attr("content"); exists && strings.TrimSpace(content) != "" {
-			imageURL = content
-			return false
-		}
-		return true
-	})
-
-	if imageURL == "" {
-		return nil
-	}
-
-	imageData, err := news.HttpGet(imageURL)
-	if err != nil || len(imageData) == 0 {
+	// AP often shares WebP pictures, with other renditions in the page's figures.
+	image, imageURL := news.ConvertFirstCandidate(news.ImageCandidates(doc, articleURL))
+	if image == nil {
 		return nil
 	}
 
@@ -188,8 +199,13 @@ func (a *AP) extractThumbnail(html string) *news.Thumbnail {
 		}
 	})
 
-	return &news.Thumbnail{
-		Image:   news.ConvertImage(imageData),
-		Caption: news.SanitizeText(caption),
-	}
+	// The feed's credit is often repeated at the end of the caption, which is all there is without it.
+	caption, credit = news.CaptionCredit(caption, credit)
+
+	pictures := []news.Thumbnail{{
+		Image:   image,
+		Caption: caption,
+		Credit:  credit,
+	}}
+	return append(pictures, news.GalleryFallbacks(doc, articleURL, imageURL, news.PictureFallbacks)...)
 }
@@ -0,0 +1,173 @@
+package news
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// BlockKind is the kind of a block of an article body.
+type BlockKind int
+
+const (
+	Paragraph BlockKind = iota
+	Subheading
+	ListItem
+	Quote
+)
+
+// Block is a paragraph, subheading, list item or quote of an article body.
+type Block struct {
+	Kind BlockKind `json:"kind"`
+	Text string    `json:"text"`
+	// Number is the position of an item in an ordered list, or zero for other blocks.
+	Number int `json:"number,omitempty"`
+}
+
+// Body is the text of an article as a list of blocks.
+type Body []Block
+
+// skippedElements hold things that aren't part of the text, such as embeds, pictures and sharing buttons.
+var skippedElements = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "iframe": true, "embed": true, "object": true,
+	"figure": true, "figcaption": true, "picture": true, "img": true, "svg": true, "video": true, "audio": true,
+	"aside": true, "nav": true, "header": true, "footer": true, "form": true, "button": true, "input": true,
+	"select": true, "textarea": true, "h1": true,
+}
+
+// inlineElements are part of the paragraph they are in.
+var inlineElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "cite": true, "code": true, "data": true,
+	"dfn": true, "em": true, "i": true, "kbd": true, "mark": true, "q": true, "s": true, "samp": true, "small": true,
+	"span": true, "strong": true, "sub": true, "sup": true, "time": true, "u": true, "var": true, "wbr": true,
+}
+
+// ParseBody converts the HTML of an article body into blocks. The selection can be the element holding the
+// body, or its paragraphs, subheadings and lists. Paragraphs only made of links, which are cross links to other
+// articles, are left out.
+func ParseBody(selection *goquery.Selection) Body {
+	parser := &bodyParser{}
+	outermost(selection).Each(func(i int, s *goquery.Selection) {
+		parser.element(s, Paragraph)
+	})
+
+	return parser.body
+}
+
+// outermost leaves out the elements of selection inside another of its elements, such as a paragraph in a list item
+// of a selected list, as they are parsed along with it.
+func outermost(selection *goquery.Selection) *goquery.Selection {
+	return selection.FilterFunction(func(i int, s *goquery.Selection) bool {
+		return s.Parents().FilterSelection(selection).Length() == 0
+	})
+}
+
+// ParseBodyHTML converts an HTML fragment, such as the text of an article given by an API, into blocks.
+func ParseBodyHTML(fragment string) Body {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fragment))
+	if err != nil {
+		return nil
+	}
+
+	return ParseBody(doc.Find("body"))
+}
+
+type bodyParser struct {
+	body        Body
+	boilerplate *Boilerplate
+}
+
+// element adds the blocks of an element. Paragraphs in it are of the given kind, unless the element sets its own.
+func (p *bodyParser) element(s *goquery.Selection, kind BlockKind) {
+	name := goquery.NodeName(s)
+	switch name {
+	case "h2", "h3", "h4", "h5", "h6":
+		p.contents(s, Subheading)
+	case "blockquote":
+		p.contents(s, Quote)
+	case "ul", "ol":
+		s.ChildrenFiltered("li").Each(func(i int, item *goquery.Selection) {
+			start := len(p.body)
+			p.contents(item, ListItem)
+			if name == "ol" && start < len(p.body) {
+				p.body[start].Number = i + 1
+			}
+		})
+	case "li":
+		p.contents(s, ListItem)
+	default:
+		if !skippedElements[name] {
+			p.contents(s, kind)
+		}
+	}
+}
+
+// contents adds the blocks of the children of s. Text and inline elements are gathered into blocks of the given
+// kind, which line breaks and other elements end. Boilerplate is left out before the text is sanitized.
+func (p *bodyParser) contents(s *goquery.Selection, kind BlockKind) {
+	var run strings.Builder
+	linksOnly := true
+	flush := func() {
+		text := strings.Join(strings.Fields(run.String()), " ")
+		if !linksOnly && !p.boilerplate.drops(text) {
+			if text = SanitizeText(text); text != "" {
+				p.body = append(p.body, Block{Kind: kind, Text: text})
+			}
+		}
+		run.Reset()
+		linksOnly = true
+	}
+
+	s.Contents().Each(func(i int, child *goquery.Selection) {
+		node := child.Get(0)
+		switch {
+		case node.Type == html.TextNode:
+			run.WriteString(node.Data)
+			linksOnly = linksOnly && strings.TrimSpace(node.Data) == ""
+		case node.Type != html.ElementNode:
+		case node.Data == "br":
+			flush()
+		case inlineElements[node.Data]:
+			text := child.Text()
+			run.WriteString(text)
+			if node.Data != "a" && child.Find("a").Length() == 0 {
+				linksOnly = linksOnly && strings.TrimSpace(text) == ""
+			}
+		default:
+			flush()
+			p.element(child, kind)
+		}
+	})
+	flush()
+}
+
+// Render formats the body for the article text of the News Channel, which has no styling. Blocks are separated by
+// a blank line, except for list items, which are marked with a bullet or their number, and the block following a
+// subheading.
+func (b Body) Render() string {
+	var builder strings.Builder
+	for i, block := range b {
+		if i != 0 {
+			previous := b[i-1]
+			if previous.Kind == Subheading || (previous.Kind == ListItem && block.Kind == ListItem) {
+				builder.WriteString("\n")
+			} else {
+				builder.WriteString("\n\n")
+			}
+		}
+
+		if block.Kind == ListItem {
+			if block.Number != 0 {
+				builder.WriteString(fmt.Sprintf("%d. ", block.Number))
+			} else {
+				builder.WriteString("• ")
+			}
+		}
+
+		builder.WriteString(block.Text)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,111 @@
+package news
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const bodyPage = `<html><body><div class="story">
+	<p>ROME (AP) — The council approved the <a href="/bridge">new bridge</a> on Monday.</p>
+	<figure><img src="/bridge.jpg"><figcaption>The bridge.</figcaption></figure>
+	<p><a href="/other">Read more: Why the old bridge closed</a></p>
+	<h2>What happens next</h2>
+	<p>Work starts in <strong>spring</strong>.<br>It will last two years.</p>
+	<ul><li>Cars will be diverted.</li><li>Boats <em>can</em> pass.</li></ul>
+	<ol><li>Demolition</li><li>Construction</li></ol>
+	<blockquote><p>“It is a great day,” the mayor said.</p></blockquote>
+	<div class="share"><button>Share</button><script>track()</script></div>
+	Text without a paragraph &amp; more.
+</div></body></html>`
+
+func TestParseBody(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(bodyPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Body{
+		{Kind: Paragraph, Text: "ROME (AP) — The council approved the new bridge on Monday."},
+		{Kind: Subheading, Text: "What happens next"},
+		{Kind: Paragraph, Text: "Work starts in spring."},
+		{Kind: Paragraph, Text: "It will last two years."},
+		{Kind: ListItem, Text: "Cars will be diverted."},
+		{Kind: ListItem, Text: "Boats can pass."},
+		{Kind: ListItem, Text: "Demolition", Number: 1},
+		{Kind: ListItem, Text: "Construction", Number: 2},
+		{Kind: Quote, Text: "“It is a great day,” the mayor said."},
+		{Kind: Paragraph, Text: "Text without a paragraph & more."},
+	}
+
+	actual := ParseBody(doc.Find("div.story"))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+}
+
+func TestParseBodyNestedSelection(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<div class="story">
+		<p>Lead.</p>
+		<ul><li><p>First item</p></li><li>Second item</li></ul>
+		<blockquote><p>A quote.</p></blockquote>
+	</div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Paragraphs inside selected lists are part of the list, not paragraphs of their own.
+	expected := Body{
+		{Kind: Paragraph, Text: "Lead."},
+		{Kind: ListItem, Text: "First item"},
+		{Kind: ListItem, Text: "Second item"},
+		{Kind: Paragraph, Text: "A quote."},
+	}
+
+	actual := ParseBody(doc.Find("div.story").Find("p, h2, h3, ul"))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+}
+
+func TestParseBodyHTML(t *testing.T) {
+	expected := Body{
+		{Kind: Paragraph, Text: "First paragraph."},
+		{Kind: Paragraph, Text: "Second paragraph."},
+	}
+
+	actual := ParseBodyHTML("<p>First paragraph.</p><p>Second @@NOTICIA[123]paragraph.</p>")
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+
+	if body := ParseBodyHTML("Plain text"); !reflect.DeepEqual(body, Body{{Kind: Paragraph, Text: "Plain text"}}) {
+		t.Errorf("unexpected body %+v", body)
+	}
+}
+
+func TestRenderBody(t *testing.T) {
+	body := Body{
+		{Kind: Paragraph, Text: "The council approved the bridge."},
+		{Kind: Subheading, Text: "What happens next"},
+		{Kind: Paragraph, Text: "Work starts in spring."},
+		{Kind: ListItem, Text: "Cars will be diverted."},
+		{Kind: ListItem, Text: "Boats can pass."},
+		{Kind: ListItem, Text: "Demolition", Number: 1},
+		{Kind: Quote, Text: "“It is a great day,” the mayor said."},
+	}
+
+	expected := "The council approved the bridge.\n\n" +
+		"What happens next\n" +
+		"Work starts in spring.\n\n" +
+		"• Cars will be diverted.\n" +
+		"• Boats can pass.\n" +
+		"1. Demolition\n\n" +
+		"“It is a great day,” the mayor said."
+
+	if actual := body.Render(); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
@@ -0,0 +1,220 @@
+package news
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// boilerplateVersion is the newest version of the boilerplate rules format we understand.
+const boilerplateVersion = 1
+
+// Boilerplate are the rules removing text that isn't part of the articles of a source, such as newsletter sign-ups,
+// cross links, photo credits and social media embeds, from their bodies.
+type Boilerplate struct {
+	Version int    `json:"version"`
+	Source  string `json:"source"`
+	// Selectors are CSS selectors of elements to remove before the body is parsed.
+	Selectors []string `json:"selectors"`
+	// Lines are regular expressions. Paragraphs containing a match are left out.
+	Lines []string `json:"lines"`
+	// Phrases leave out paragraphs starting with them, ignoring case.
+	Phrases []string `json:"phrases"`
+
+	// Where the file was read from, used for reporting.
+	path     string
+	matchers []cascadia.Selector
+	lines    []*regexp.Regexp
+}
+
+//go:embed data/boilerplate/*.json
+var boilerplateFS embed.FS
+
+// boilerplate maps source names to their rules.
+var boilerplate = map[string]*Boilerplate{}
+
+func init() {
+	// The embedded rules are validated by the tests, so failing here means the binary itself is broken.
+	err := LoadBoilerplate("")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// LoadBoilerplate loads the rules of every source from the embedded files.
+// If dir is not empty, any <source>.json file inside it replaces the embedded rules of that source.
+func LoadBoilerplate(dir string) error {
+	files, err := ReadBoilerplate(dir)
+	if err != nil {
+		return err
+	}
+
+	rules := make(map[string]*Boilerplate)
+	for _, file := range files {
+		if file.Version > boilerplateVersion {
+			return fmt.Errorf("%s: unsupported boilerplate version %d", file.path, file.Version)
+		}
+
+		err = file.compile()
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.path, err)
+		}
+
+		rules[file.Source] = file
+	}
+
+	boilerplate = rules
+	return nil
+}
+
+// ReadBoilerplate reads the rules of every source, preferring the files found in dir over the embedded copies.
+func ReadBoilerplate(dir string) ([]*Boilerplate, error) {
+	sources, err := readDataFiles(boilerplateFS, "data/boilerplate/*.json", dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*Boilerplate
+	for _, src := range sources {
+		file := &Boilerplate{path: src.path}
+		err = json.Unmarshal(src.data, file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src.path, err)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// ValidateBoilerplate checks the rules that LoadBoilerplate would use and returns every problem found.
+func ValidateBoilerplate(dir string) []error {
+	files, err := ReadBoilerplate(dir)
+	if err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+	report := func(file *Boilerplate, format string, args ...any) {
+		problems = append(problems, fmt.Errorf("%s: %s", file.path, fmt.Sprintf(format, args...)))
+	}
+
+	for _, file := range files {
+		if file.Version < 1 || file.Version > boilerplateVersion {
+			report(file, "unsupported version %d", file.Version)
+		}
+
+		source := strings.TrimSuffix(filepath.Base(file.path), ".json")
+		if file.Source != source {
+			report(file, "source %q does not match the file name", file.Source)
+		}
+
+		for _, selector := range file.Selectors {
+			if _, err := cascadia.Compile(selector); err != nil {
+				report(file, "invalid selector %q: %v", selector, err)
+			}
+		}
+		for _, line := range file.Lines {
+			if _, err := regexp.Compile(line); err != nil {
+				report(file, "invalid line pattern %q: %v", line, err)
+			}
+		}
+		for _, phrase := range file.Phrases {
+			if strings.TrimSpace(phrase) == "" {
+				report(file, "empty phrase would leave out every paragraph")
+			}
+		}
+	}
+
+	return problems
+}
+
+func (b *Boilerplate) compile() error {
+	for _, selector := range b.Selectors {
+		matcher, err := cascadia.Compile(selector)
+		if err != nil {
+			return fmt.Errorf("invalid selector %q: %w", selector, err)
+		}
+		b.matchers = append(b.matchers, matcher)
+	}
+
+	for _, line := range b.Lines {
+		pattern, err := regexp.Compile(line)
+		if err != nil {
+			return fmt.Errorf("invalid line pattern %q: %w", line, err)
+		}
+		b.lines = append(b.lines, pattern)
+	}
+
+	return nil
+}
+
+// BoilerplateFor returns the rules of a source. Sources without rules get nil, which removes nothing.
+func BoilerplateFor(source string) *Boilerplate {
+	return boilerplate[source]
+}
+
+// Strip removes the elements matching the selectors from selection, and returns selection without the elements
+// that match them themselves or sit inside an element that does, such as a paragraph of an embedded post.
+func (b *Boilerplate) Strip(selection *goquery.Selection) *goquery.Selection {
+	if b == nil {
+		return selection
+	}
+
+	for _, matcher := range b.matchers {
+		selection.FindMatcher(matcher).Remove()
+		selection = selection.FilterFunction(func(i int, s *goquery.Selection) bool {
+			return !s.IsMatcher(matcher) && s.ParentsMatcher(matcher).Length() == 0
+		})
+	}
+
+	return selection
+}
+
+// drops reports whether a paragraph is boilerplate. The text has its whitespace collapsed but is not sanitized yet.
+func (b *Boilerplate) drops(text string) bool {
+	if b == nil {
+		return false
+	}
+
+	for _, pattern := range b.lines {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+
+	for _, phrase := range b.Phrases {
+		if len(text) >= len(phrase) && strings.EqualFold(text[:len(phrase)], phrase) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseBody is like the ParseBody function, leaving out the boilerplate of the source.
+func (b *Boilerplate) ParseBody(selection *goquery.Selection) Body {
+	parser := &bodyParser{boilerplate: b}
+	outermost(b.Strip(selection)).Each(func(i int, s *goquery.Selection) {
+		parser.element(s, Paragraph)
+	})
+
+	return parser.body
+}
+
+// ParseBodyHTML is like the ParseBodyHTML function, leaving out the boilerplate of the source.
+func (b *Boilerplate) ParseBodyHTML(fragment string) Body {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fragment))
+	if err != nil {
+		return nil
+	}
+
+	return b.ParseBody(doc.Find("body"))
+}
@@ -0,0 +1,94 @@
+package news
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedBoilerplateIsValid(t *testing.T) {
+	for _, problem := range ValidateBoilerplate("") {
+		t.Error(problem)
+	}
+}
+
+// TestBoilerplateFixtures parses the saved article body of each source with and without its rules. The text
+// without the rules keeps the boilerplate, and the text with them must match the expected text. The sources
+// also extract the same fixtures through their own selections in their TestExtractArticleBodyBoilerplate.
+func TestBoilerplateFixtures(t *testing.T) {
+	files, err := ReadBoilerplate("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		t.Run(file.Source, func(t *testing.T) {
+			page, err := os.ReadFile(filepath.Join("testdata", "boilerplate", file.Source+".html"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, err := os.ReadFile(filepath.Join("testdata", "boilerplate", file.Source+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			before := ParseBodyHTML(string(page)).Render()
+			after := BoilerplateFor(file.Source).ParseBodyHTML(string(page)).Render()
+			if after != strings.TrimSpace(string(expected)) {
+				t.Errorf("expected:\n%s\ngot:\n%s", expected, after)
+			}
+			if len(before) <= len(after) {
+				t.Errorf("expected the rules to remove text from:\n%s", before)
+			}
+		})
+	}
+}
+
+func TestBoilerplateOverride(t *testing.T) {
+	dir := t.TempDir()
+	data := `{
+  "version": 1,
+  "source": "nos",
+  "selectors": ["div.social", "p["],
+  "lines": ["^Lees ook", "(unclosed"],
+  "phrases": ["Volg NOS", " "]
+}`
+	err := os.WriteFile(filepath.Join(dir, "ap.json"), []byte(data), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Source not matching the file name, invalid selector, invalid pattern and empty phrase.
+	problems := ValidateBoilerplate(dir)
+	if len(problems) != 4 {
+		t.Errorf("expected 4 problems, got %d: %v", len(problems), problems)
+	}
+
+	t.Cleanup(func() {
+		_ = LoadBoilerplate("")
+	})
+
+	if err = LoadBoilerplate(dir); err == nil {
+		t.Error("expected invalid rules to fail to load")
+	}
+
+	data = `{"version": 1, "source": "ap", "selectors": ["div.social"], "phrases": ["Volg NOS"]}`
+	err = os.WriteFile(filepath.Join(dir, "ap.json"), []byte(data), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = LoadBoilerplate(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := BoilerplateFor("ap").ParseBodyHTML(`<p>Sign up for our newsletter.</p><div class="social">Share</div><p>volg NOS op Instagram</p>`)
+	if actual := body.Render(); actual != "Sign up for our newsletter." {
+		t.Errorf("expected the override to replace the embedded rules, got %q", actual)
+	}
+	if BoilerplateFor("france24") == nil {
+		t.Error("embedded rules of other sources should still be loaded")
+	}
+}
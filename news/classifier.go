@@ -0,0 +1,254 @@
+package news
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// topicDataVersion is the newest version of the topic training data format we understand.
+const topicDataVersion = 1
+
+// TopicData is the contents of a single language's topic training data file. Examples are typical headlines and
+// sentences of each topic, keyed by the topic key.
+type TopicData struct {
+	Version  int                 `json:"version"`
+	Language string              `json:"language"`
+	Examples map[string][]string `json:"examples"`
+
+	// Where the file was read from, used for reporting.
+	path string
+}
+
+// TopicClassification decides when the topic classifier may change the topic an article's feed gave it.
+type TopicClassification struct {
+	Enabled bool `xml:"Enabled"`
+	// Threshold is the lowest confidence, from 0 to 1, at which the classifier's topic replaces the feed's.
+	Threshold float64 `xml:"Threshold"`
+}
+
+var DefaultTopicClassification = TopicClassification{
+	Enabled:   true,
+	Threshold: 0.8,
+}
+
+// Classification is how articles are classified.
+var Classification = DefaultTopicClassification
+
+// Validate checks that the threshold is a confidence.
+func (c TopicClassification) Validate() error {
+	if c.Threshold < 0 || c.Threshold > 1 {
+		return errors.New("the threshold must be between 0 and 1")
+	}
+
+	return nil
+}
+
+// minimumExamples is the fewest examples a topic needs to be told apart from the others.
+const minimumExamples = 5
+
+//go:embed data/topics/*.json
+var topicDataFS embed.FS
+
+// TopicClassifier is a naive Bayes classifier telling the topic of an article from the words of its text.
+type TopicClassifier struct {
+	language string
+	// counts holds how often each word appears in the examples of each topic, and totals the number of words.
+	counts     map[Topic]map[string]int
+	totals     map[Topic]int
+	vocabulary map[string]bool
+}
+
+// classifiers maps language tags to their classifier. Languages without training data have none.
+var classifiers = map[string]*TopicClassifier{}
+
+func init() {
+	// The embedded data is validated by the tests, so failing here means the binary itself is broken.
+	err := LoadTopicData("")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// LoadTopicData trains the classifier of every language with training data from the embedded files.
+// If dir is not empty, any <language>.json file inside it replaces the embedded file for that language.
+func LoadTopicData(dir string) error {
+	files, err := ReadTopicData(dir)
+	if err != nil {
+		return err
+	}
+
+	trained := make(map[string]*TopicClassifier)
+	for _, file := range files {
+		if file.Version > topicDataVersion {
+			return fmt.Errorf("%s: unsupported topic data version %d", file.path, file.Version)
+		}
+
+		classifier, err := NewTopicClassifier(file.Language, file.Examples)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.path, err)
+		}
+		trained[file.Language] = classifier
+	}
+
+	classifiers = trained
+	return nil
+}
+
+// ReadTopicData reads every language's training data, preferring the files found in dir over the embedded copies.
+func ReadTopicData(dir string) ([]TopicData, error) {
+	sources, err := readDataFiles(topicDataFS, "data/topics/*.json", dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []TopicData
+	for _, src := range sources {
+		file := TopicData{path: src.path}
+		err = json.Unmarshal(src.data, &file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src.path, err)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// ValidateTopicData checks the training data that LoadTopicData would use and returns every problem found. Topics
+// are checked against the loaded feeds.
+func ValidateTopicData(dir string) []error {
+	files, err := ReadTopicData(dir)
+	if err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+	report := func(file TopicData, format string, args ...any) {
+		problems = append(problems, fmt.Errorf("%s: %s", file.path, fmt.Sprintf(format, args...)))
+	}
+
+	for _, file := range files {
+		if file.Version < 1 || file.Version > topicDataVersion {
+			report(file, "unsupported version %d", file.Version)
+		}
+
+		language := strings.TrimSuffix(filepath.Base(file.path), ".json")
+		if file.Language != language {
+			report(file, "language %q does not match the file name", file.Language)
+		}
+
+		for _, topic := range BuiltinTopics {
+			if examples := file.Examples[topic.String()]; len(examples) < minimumExamples {
+				report(file, "topic %q has %d examples, at least %d are needed", topic, len(examples), minimumExamples)
+			}
+		}
+
+		for key, examples := range file.Examples {
+			if topic, err := ParseTopic(key); err != nil {
+				report(file, "%v", err)
+			} else if !KnownTopic(topic) {
+				report(file, "topic %q is not filled by any source", key)
+			}
+			for _, example := range examples {
+				if len(Tokenize(example, file.Language)) == 0 {
+					report(file, "example %q of topic %q has no words left after removing stop words", example, key)
+				}
+			}
+		}
+	}
+
+	return problems
+}
+
+// NewTopicClassifier trains a classifier with examples of each topic, keyed by the topic key.
+func NewTopicClassifier(language string, examples map[string][]string) (*TopicClassifier, error) {
+	classifier := &TopicClassifier{
+		language:   language,
+		counts:     make(map[Topic]map[string]int),
+		totals:     make(map[Topic]int),
+		vocabulary: make(map[string]bool),
+	}
+
+	for key, texts := range examples {
+		topic, err := ParseTopic(key)
+		if err != nil {
+			return nil, err
+		}
+
+		classifier.counts[topic] = make(map[string]int)
+		for _, text := range texts {
+			for _, token := range Tokenize(text, language) {
+				classifier.counts[topic][token]++
+				classifier.totals[topic]++
+				classifier.vocabulary[token] = true
+			}
+		}
+	}
+
+	return classifier, nil
+}
+
+// ClassifierForLanguage returns the classifier of a language tag, or nil if there is no training data for it.
+func ClassifierForLanguage(language string) *TopicClassifier {
+	return classifiers[language]
+}
+
+// Classify returns the most likely of the given topics for text, or of every trained topic if none are given, along
+// with the probability the classifier gives it. Every topic is considered equally likely before looking at the
+// text, so text without known words gets an even probability.
+func (c *TopicClassifier) Classify(text string, topics ...Topic) (Topic, float64) {
+	if len(topics) == 0 {
+		for topic := range c.counts {
+			topics = append(topics, topic)
+		}
+	}
+
+	tokens := Tokenize(text, c.language)
+	scores := make([]float64, len(topics))
+	for i, topic := range topics {
+		for _, token := range tokens {
+			if !c.vocabulary[token] {
+				continue
+			}
+
+			// Laplace smoothing keeps words never seen in a topic's examples from ruling it out.
+			scores[i] += math.Log(float64(c.counts[topic][token]+1) / float64(c.totals[topic]+len(c.vocabulary)))
+		}
+	}
+
+	best := 0
+	for i := range topics {
+		if scores[i] > scores[best] || (scores[i] == scores[best] && topics[i] < topics[best]) {
+			best = i
+		}
+	}
+
+	// The probability of the best topic is its share of the likelihoods, computed relative to it to avoid underflow.
+	sum := 0.0
+	for _, score := range scores {
+		sum += math.Exp(score - scores[best])
+	}
+
+	return topics[best], 1 / sum
+}
+
+// ClassifyArticle returns the most likely topic of an article and its probability. If the article's feed covers
+// several topics, the classifier chooses between them, otherwise between every topic.
+func (c *TopicClassifier) ClassifyArticle(article Article) (Topic, float64) {
+	text := article.Title
+	if article.Content != nil {
+		text += "\n" + *article.Content
+	}
+
+	if len(article.AlternativeTopics) == 0 {
+		return c.Classify(text)
+	}
+
+	return c.Classify(text, append([]Topic{article.Topic}, article.AlternativeTopics...)...)
+}
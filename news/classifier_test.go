@@ -0,0 +1,45 @@
+package news
+
+import "testing"
+
+func TestEmbeddedTopicDataIsValid(t *testing.T) {
+	for _, problem := range ValidateTopicData("") {
+		t.Error(problem)
+	}
+}
+
+func TestClassifyArticle(t *testing.T) {
+	tests := []struct {
+		language     string
+		title        string
+		topic        Topic
+		alternatives []Topic
+		expected     Topic
+	}{
+		// The éco-tech feed of France24 covers both science and technology.
+		{"fr", "Des astronomes découvrent une exoplanète grâce au télescope spatial", Technology, []Topic{Science}, Science},
+		{"fr", "Intelligence artificielle : un nouveau smartphone pour les utilisateurs", Technology, []Topic{Science}, Technology},
+		// Remarkable news from NOS is only loosely entertainment.
+		{"nl", "Verstappen wint de Grand Prix na pole position", Entertainment, []Topic{Sports, Science}, Sports},
+		{"en", "Stocks fall as investors worry about inflation", Business, nil, Business},
+	}
+
+	for _, test := range tests {
+		article := Article{Title: test.title, Topic: test.topic, AlternativeTopics: test.alternatives}
+		topic, confidence := ClassifierForLanguage(test.language).ClassifyArticle(article)
+		if topic != test.expected {
+			t.Errorf("%q: expected %s, got %s (confidence %.2f)", test.title, test.expected, topic, confidence)
+		}
+	}
+}
+
+func TestClassifyWithoutKnownWords(t *testing.T) {
+	topic, confidence := ClassifierForLanguage("en").Classify("Zorblax quixotic", Entertainment, Science)
+	if confidence != 0.5 {
+		t.Errorf("expected an even probability for text without known words, got %s with %.2f", topic, confidence)
+	}
+
+	if ClassifierForLanguage("xx") != nil {
+		t.Error("expected no classifier for a language without training data")
+	}
+}
@@ -0,0 +1,194 @@
+package news
+
+import (
+	"fmt"
+	"log"
+)
+
+// StoryClusterer keeps a single article for every story of a run.
+// Sources file articles under each topic on their own, so the same event can show up under several topics with
+// headlines too different for the duplicate check done while fetching.
+type StoryClusterer struct {
+	language string
+	ranks    map[Topic]int
+	shared   *SharedStories
+}
+
+// SharedStories remembers the stories kept by the countries of a language during a run,
+// so that a story is filed under the same topic in all of them.
+type SharedStories struct {
+	dedup   *Deduplicator
+	stories []sharedStory
+}
+
+type sharedStory struct {
+	country string
+	topic   Topic
+}
+
+// NewSharedStories creates an empty SharedStories for the given language.
+func NewSharedStories(language string) *SharedStories {
+	return &SharedStories{dedup: NewDeduplicator(language)}
+}
+
+// NewStoryClusterer creates a StoryClusterer preferring topics in the given order. Topics that aren't listed
+// come after the listed ones, in their usual order. shared may be nil if countries aren't clustered together.
+func NewStoryClusterer(language string, topicPriority []Topic, shared *SharedStories) *StoryClusterer {
+	ranks := make(map[Topic]int)
+	for i, topic := range topicPriority {
+		if _, ok := ranks[topic]; !ok {
+			ranks[topic] = i
+		}
+	}
+
+	return &StoryClusterer{
+		language: language,
+		ranks:    ranks,
+		shared:   shared,
+	}
+}
+
+func (c *StoryClusterer) rank(topic Topic) int {
+	if rank, ok := c.ranks[topic]; ok {
+		return rank
+	}
+
+	return len(c.ranks) + topic.order()
+}
+
+// Cluster groups the articles telling the same story and returns the articles with only one per group kept,
+// in their original order. country is used in logs and to remember the kept stories if they are shared.
+func (c *StoryClusterer) Cluster(articles []Article, country string) []Article {
+	dedup := NewDeduplicator(c.language)
+	parents := make([]int, len(articles))
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+
+	for i, article := range articles {
+		parents[i] = i
+		lead := articleLead(article)
+
+		for j := range dedup.matches(article.Title, lead) {
+			parents[find(j)] = find(i)
+		}
+
+		dedup.Add(article.Title, lead)
+	}
+
+	clusters := make(map[int][]int)
+	for i := range articles {
+		root := find(i)
+		clusters[root] = append(clusters[root], i)
+	}
+
+	kept := make([]bool, len(articles))
+	for i := range articles {
+		members := clusters[find(i)]
+		if members[0] != i {
+			// Each cluster is handled once, from its first article.
+			continue
+		}
+
+		representative := c.representative(articles, members)
+		kept[representative] = true
+
+		for _, member := range members {
+			if member == representative {
+				continue
+			}
+
+			ReportSkip(SkipEvent{
+				Reason:       SkipSameStory,
+				Source:       articles[member].Source,
+				Topic:        articles[member].Topic.String(),
+				URL:          articles[member].URL,
+				Title:        articles[member].Title,
+				MatchedTitle: articles[representative].Title,
+				Score:        dedup.similarity(member, representative),
+				Detail:       fmt.Sprintf("kept under %s", articles[representative].Topic),
+			})
+		}
+
+		if c.shared != nil {
+			article := articles[representative]
+			c.shared.dedup.Add(article.Title, articleLead(article))
+			c.shared.stories = append(c.shared.stories, sharedStory{
+				country: country,
+				topic:   article.Topic,
+			})
+		}
+	}
+
+	var result []Article
+	for i, article := range articles {
+		if kept[i] {
+			result = append(result, article)
+		}
+	}
+
+	return result
+}
+
+// representative picks the article of a cluster to keep. The topic the story was given by another country comes
+// first, then the topic priority, then the order the source returned the articles in.
+func (c *StoryClusterer) representative(articles []Article, members []int) int {
+	if len(members) == 1 {
+		return members[0]
+	}
+
+	var sharedTopic Topic
+	if c.shared != nil {
+		for _, member := range members {
+			story, ok := c.shared.find(articles[member])
+			if ok {
+				sharedTopic = story.topic
+				log.Printf("%q was filed under %s in %s", articles[member].Title, story.topic, story.country)
+				break
+			}
+		}
+	}
+
+	best := members[0]
+	for _, member := range members[1:] {
+		topic, bestTopic := articles[member].Topic, articles[best].Topic
+		if bestTopic == sharedTopic {
+			continue
+		}
+
+		if topic == sharedTopic || c.rank(topic) < c.rank(bestTopic) {
+			best = member
+		}
+	}
+
+	return best
+}
+
+// find returns the most similar story kept by another country, if any tells the same story as the article.
+func (s *SharedStories) find(article Article) (sharedStory, bool) {
+	index, bestScore := -1, 0.0
+	for i, score := range s.dedup.matches(article.Title, articleLead(article)) {
+		if score > bestScore {
+			index, bestScore = i, score
+		}
+	}
+
+	if index < 0 {
+		return sharedStory{}, false
+	}
+
+	return s.stories[index], true
+}
+
+func articleLead(article Article) string {
+	if article.Content == nil {
+		return ""
+	}
+
+	return Lead(*article.Content)
+}
@@ -0,0 +1,78 @@
+package news
+
+import "testing"
+
+func articleWithLead(title string, topic Topic, lead string) Article {
+	return Article{
+		Title:   title,
+		Topic:   topic,
+		Content: &lead,
+	}
+}
+
+const miltonLead = "Hurricane Milton made landfall near Siesta Key on Wednesday night, knocking out power to more than two million homes and businesses."
+
+func TestClusterPrefersTopicPriority(t *testing.T) {
+	articles := []Article{
+		articleWithLead("Storm hits Florida", InternationalNews, miltonLead),
+		articleWithLead("Apple unveils new iPhone with faster chip", Technology, ""),
+		articleWithLead("Millions without power as Milton makes landfall", NationalNews, miltonLead),
+	}
+
+	clusterer := NewStoryClusterer("en", []Topic{NationalNews, InternationalNews}, nil)
+	result := clusterer.Cluster(articles, "United States")
+
+	if len(result) != 2 {
+		t.Fatalf("expected 2 articles, got %d", len(result))
+	}
+	if result[0].Title != "Apple unveils new iPhone with faster chip" || result[1].Topic != NationalNews {
+		t.Errorf("expected the national article to be kept, got %q and %q", result[0].Title, result[1].Title)
+	}
+
+	clusterer = NewStoryClusterer("en", []Topic{InternationalNews}, nil)
+	result = clusterer.Cluster(articles, "United States")
+	if len(result) != 2 || result[0].Topic != InternationalNews {
+		t.Errorf("expected the international article to be kept, got %+v", result)
+	}
+}
+
+func TestClusterSharedAcrossCountries(t *testing.T) {
+	shared := NewSharedStories("en")
+	NewStoryClusterer("en", []Topic{InternationalNews}, shared).Cluster([]Article{
+		articleWithLead("Storm hits Florida", InternationalNews, miltonLead),
+	}, "Canada")
+
+	// The story was filed under international news in Canada, which wins over the national priority.
+	result := NewStoryClusterer("en", nil, shared).Cluster([]Article{
+		articleWithLead("Millions without power as Milton makes landfall", NationalNews, miltonLead),
+		articleWithLead("Storm hits Florida", InternationalNews, miltonLead),
+	}, "United Kingdom")
+
+	if len(result) != 1 || result[0].Topic != InternationalNews {
+		t.Errorf("expected the international article to be kept, got %+v", result)
+	}
+}
+
+func TestClusterReportsSuppressed(t *testing.T) {
+	var events []SkipEvent
+	SkipHandler = func(event SkipEvent) {
+		events = append(events, event)
+	}
+	defer func() { SkipHandler = nil }()
+
+	storm := articleWithLead("Storm hits Florida", InternationalNews, miltonLead)
+	storm.Source, storm.URL = "ap", "https://apnews.com/article/storm"
+	NewStoryClusterer("en", nil, nil).Cluster([]Article{
+		storm,
+		articleWithLead("Millions without power as Milton makes landfall", NationalNews, miltonLead),
+	}, "United States")
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 skip event, got %d", len(events))
+	}
+	if events[0].Reason != SkipSameStory || events[0].Title != "Storm hits Florida" ||
+		events[0].Source != "ap" || events[0].URL != "https://apnews.com/article/storm" ||
+		events[0].MatchedTitle != "Millions without power as Milton makes landfall" || events[0].Score < 0.55 {
+		t.Errorf("unexpected skip event %+v", events[0])
+	}
+}
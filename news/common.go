@@ -1,5 +1,13 @@
 package news
 
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+)
+
 // Source represents a News source.
 type Source interface {
 	GetArticles() ([]Article, error)
@@ -8,29 +16,122 @@ type Source interface {
 }
 
 type Article struct {
-	Title     string
-	Content   *string
-	Topic     Topic
-	Location  *Location
-	Thumbnail *Thumbnail
+	Title string
+	// Source is the name of the source the article was found by, and URL the page it was read from.
+	Source string
+	URL    string
+	// Content is the text written into the news file. Sources giving a structured Body render it from that.
+	Content *string
+	Body    Body
+	// OriginalLength is the length in UTF-16 units of the body before it was summarized, or zero if it wasn't.
+	OriginalLength int
+	Topic          Topic
+	// AlternativeTopics are the other topics the article's feed covers, which the topic classifier may move it to.
+	AlternativeTopics []Topic
+	Location          *Location
+	// Pictures are the article's lead picture followed by the pictures to fall back on if it can't be used or doesn't
+	// fit the image budget. Only the lead picture is converted up front, the others once they are needed.
+	Pictures []Thumbnail
+	// When the article was first published and last updated. Zero if the source doesn't say.
+	PublishedTime time.Time
+	UpdatedTime   time.Time
 }
 
 type Thumbnail struct {
 	Image   []byte
 	Caption string
+	// Credit is the photographer or agency the picture is attributed to.
+	Credit string
+
+	// candidates are the URLs of a picture that is only downloaded and converted once it is needed.
+	candidates []string
+	userAgent  []string
+}
+
+// LazyThumbnail returns a picture that Load converts from the first of candidates that can be used.
+func LazyThumbnail(candidates []string, caption string, credit string, userAgent ...string) Thumbnail {
+	return Thumbnail{
+		Caption:    caption,
+		Credit:     credit,
+		candidates: candidates,
+		userAgent:  userAgent,
+	}
+}
+
+// Load downloads and converts a lazy picture, if it wasn't already. It returns whether the picture can be used.
+func (t *Thumbnail) Load() bool {
+	if len(t.Image) == 0 && len(t.candidates) != 0 {
+		t.Image = ConvertFirstImage(t.candidates, t.userAgent...)
+		t.candidates = nil
+	}
+
+	return len(t.Image) != 0
 }
 
-// Topic represents a news topic.
-type Topic int
+// Topic is the key of a news topic, such as "sports". Countries define the topics they show, and sources the ones
+// they can fill, so any key can be used besides the built-in ones.
+type Topic string
 
 const (
-	NationalNews Topic = iota
-	InternationalNews
-	Sports
-	Entertainment
-	Business
-	Science
-	Technology
+	NationalNews      Topic = "national"
+	InternationalNews Topic = "international"
+	Sports            Topic = "sports"
+	Entertainment     Topic = "entertainment"
+	Business          Topic = "business"
+	Science           Topic = "science"
+	Technology        Topic = "technology"
 )
 
+// BuiltinTopics are the topics every country shows unless it defines its own, in their usual order.
+var BuiltinTopics = []Topic{NationalNews, InternationalNews, Sports, Entertainment, Business, Science, Technology}
+
+var topicKeyFormat = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// String returns the key used to refer to the topic in configuration.
+func (t Topic) String() string {
+	return string(t)
+}
+
+// order returns the position of a topic in the usual order. Topics that aren't built in come last.
+func (t Topic) order() int {
+	index := slices.Index(BuiltinTopics, t)
+	if index == -1 {
+		return len(BuiltinTopics)
+	}
+
+	return index
+}
+
+// UnmarshalJSON reads a topic key. Articles stored before topics were keys hold the index of a built-in topic,
+// which is converted to its key.
+func (t *Topic) UnmarshalJSON(data []byte) error {
+	var index int
+	if json.Unmarshal(data, &index) == nil {
+		if index < 0 || index >= len(BuiltinTopics) {
+			return fmt.Errorf("unknown topic index %d", index)
+		}
+
+		*t = BuiltinTopics[index]
+		return nil
+	}
+
+	var key string
+	err := json.Unmarshal(data, &key)
+	if err != nil {
+		return err
+	}
+
+	*t, err = ParseTopic(key)
+	return err
+}
+
+// ParseTopic returns the topic with the given key. Keys are made of lower case letters, digits and underscores.
+func ParseTopic(key string) (Topic, error) {
+	if !topicKeyFormat.MatchString(key) {
+		return "", fmt.Errorf("invalid topic key %q", key)
+	}
+
+	return Topic(key), nil
+}
+
 var RSSHubAddress string
@@ -0,0 +1,105 @@
+package news
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// creditPatterns find photo credits that sources append to their captions, such as "(AP Photo/Jane Doe)",
+// "REUTERS/John Doe/File Photo", "Foto: dpa" or "© AFP". The first group is the credit.
+var creditPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\s*\(([^()]*\b(?:Photo|Foto|Bild|Photograph)\b[^()]*)\)\s*$`),
+	regexp.MustCompile(`(?i)\s*[-–|]?\s*\b(?:Photo|Foto|Bild|Image|Crédit|Credit)s?\s*:\s*(.+)$`),
+	regexp.MustCompile(`\s*[-–|]?\s*(©.+)$`),
+	regexp.MustCompile(`\s*[-–|]?\s*\b((?:REUTERS|AFP|EFE|ANSA|dpa|Getty Images)/\S.*)$`),
+}
+
+// SplitCredit separates a photo credit at the end of a caption from the caption itself.
+// The caption is returned unchanged, with an empty credit, if it has none.
+func SplitCredit(caption string) (string, string) {
+	caption = strings.TrimSpace(caption)
+	for _, pattern := range creditPatterns {
+		match := pattern.FindStringSubmatchIndex(caption)
+		if match == nil {
+			continue
+		}
+
+		credit := strings.TrimSpace(caption[match[2]:match[3]])
+		rest := strings.TrimRight(caption[:match[0]], " -–|")
+		return rest, credit
+	}
+
+	return caption, ""
+}
+
+// CaptionCredit returns the caption and credit of a picture, given the credit its source states separately.
+// SplitCredit is only used to find the credit in the caption when the source states none. A credit the caption
+// repeats at its end is removed from it either way.
+func CaptionCredit(caption string, credit string) (string, string) {
+	caption = SanitizeText(caption)
+	credit = SanitizeText(credit)
+
+	rest, found := SplitCredit(caption)
+	if credit == "" {
+		return rest, found
+	}
+	if found != "" && strings.Contains(strings.ToLower(found), strings.ToLower(credit)) {
+		return rest, credit
+	}
+
+	return caption, credit
+}
+
+// creditSelector finds the elements of a figure that hold its credit rather than its caption.
+const creditSelector = `[itemprop="creditText"], [itemprop="copyrightHolder"], [class*="credit"], [class*="Credit"]`
+
+// FigureCredit returns the text of the element of a figure marked up as its credit, or an empty string if it has none.
+func FigureCredit(figure *goquery.Selection) string {
+	return strings.TrimSpace(figure.Find(creditSelector).First().Text())
+}
+
+// FigureCaptionCredit returns the caption and credit of a figure on an article page. The credit is read from the
+// element marked up as one if there is such an element, and is otherwise looked for at the end of the caption.
+func FigureCaptionCredit(figure *goquery.Selection) (string, string) {
+	// The credit element is often inside the figcaption, whose parts are commonly spans without spaces between them.
+	figcaption := figure.Find("figcaption").First().Clone()
+	figcaption.Find(creditSelector).Remove()
+
+	var parts []string
+	figcaption.Contents().Each(func(i int, s *goquery.Selection) {
+		if text := strings.TrimSpace(s.Text()); text != "" {
+			parts = append(parts, text)
+		}
+	})
+
+	return CaptionCredit(strings.Join(parts, " "), FigureCredit(figure))
+}
+
+// ArcImageCredit returns the credit of an image of the Arc content format used by Reuters: its photographers,
+// preceded by their agency as in "REUTERS/Jane Doe", or its byline if it has no credits.
+func ArcImageCredit(image map[string]any) string {
+	credits, _ := image["credits"].(map[string]any)
+	names := func(key string) string {
+		var result []string
+		entries, _ := credits[key].([]any)
+		for _, entry := range entries {
+			if name, _ := entry.(map[string]any)["name"].(string); strings.TrimSpace(name) != "" {
+				result = append(result, strings.TrimSpace(name))
+			}
+		}
+		return strings.Join(result, ", ")
+	}
+
+	by, affiliation := names("by"), names("affiliation")
+	switch {
+	case by != "" && affiliation != "":
+		return affiliation + "/" + by
+	case by != "" || affiliation != "":
+		return by + affiliation
+	}
+
+	byline, _ := image["byline"].(string)
+	return strings.TrimSpace(byline)
+}
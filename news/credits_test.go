@@ -0,0 +1,96 @@
+package news
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestSplitCredit(t *testing.T) {
+	tests := []struct {
+		caption         string
+		expectedCaption string
+		expectedCredit  string
+	}{
+		{"President Biden speaks in Washington. (AP Photo/Evan Vucci)", "President Biden speaks in Washington.", "AP Photo/Evan Vucci"},
+		{"Traders work on the floor of the NYSE. REUTERS/Brendan McDermid/File Photo", "Traders work on the floor of the NYSE.", "REUTERS/Brendan McDermid/File Photo"},
+		{"Pedro Sánchez en el Congreso. EFE/Mariscal", "Pedro Sánchez en el Congreso.", "EFE/Mariscal"},
+		{"Le président à l'Élysée. © AFP", "Le président à l'Élysée.", "© AFP"},
+		{"Olaf Scholz im Bundestag | Bild: picture alliance/dpa", "Olaf Scholz im Bundestag", "picture alliance/dpa"},
+		{"A photo of the harbour at dawn", "A photo of the harbour at dawn", ""},
+		{"", "", ""},
+	}
+
+	for _, test := range tests {
+		caption, credit := SplitCredit(test.caption)
+		if caption != test.expectedCaption || credit != test.expectedCredit {
+			t.Errorf("SplitCredit(%q): expected %q and %q, got %q and %q",
+				test.caption, test.expectedCaption, test.expectedCredit, caption, credit)
+		}
+	}
+}
+
+func TestCaptionCredit(t *testing.T) {
+	tests := []struct {
+		caption         string
+		credit          string
+		expectedCaption string
+		expectedCredit  string
+	}{
+		// The stated credit wins over anything that looks like one in the caption.
+		{"Fans of the band Photo: The Musical queue outside.", "Jane Doe", "Fans of the band Photo: The Musical queue outside.", "Jane Doe"},
+		// A caption repeating the credit loses it.
+		{"Traders work on the floor of the NYSE. REUTERS/Brendan McDermid", "Brendan McDermid", "Traders work on the floor of the NYSE.", "Brendan McDermid"},
+		// Without a stated credit, it is looked for in the caption.
+		{"President Biden speaks in Washington. (AP Photo/Evan Vucci)", "", "President Biden speaks in Washington.", "AP Photo/Evan Vucci"},
+	}
+
+	for _, test := range tests {
+		caption, credit := CaptionCredit(test.caption, test.credit)
+		if caption != test.expectedCaption || credit != test.expectedCredit {
+			t.Errorf("CaptionCredit(%q, %q): expected %q and %q, got %q and %q",
+				test.caption, test.credit, test.expectedCaption, test.expectedCredit, caption, credit)
+		}
+	}
+}
+
+func TestFigureCaptionCredit(t *testing.T) {
+	page := `<figure><img src="/a.jpg"><figcaption><span>Le président à l'Élysée.</span><span class="a-figcaption__credit">AFP</span></figcaption></figure>
+		<figure><img src="/b.jpg"><figcaption>Olaf Scholz im Bundestag | Bild: picture alliance/dpa</figcaption></figure>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][2]string{
+		{"Le président à l'Élysée.", "AFP"},
+		{"Olaf Scholz im Bundestag", "picture alliance/dpa"},
+	}
+	doc.Find("figure").Each(func(i int, figure *goquery.Selection) {
+		caption, credit := FigureCaptionCredit(figure)
+		if caption != expected[i][0] || credit != expected[i][1] {
+			t.Errorf("figure %d: expected %q, got %q and %q", i, expected[i], caption, credit)
+		}
+	})
+}
+
+func TestArcImageCredit(t *testing.T) {
+	var image map[string]any
+	err := json.Unmarshal([]byte(`{"caption": "A caption", "credits": {"by": [{"name": "Kim Kyung-Hoon"}], "affiliation": [{"name": "REUTERS"}]}}`), &image)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if credit := ArcImageCredit(image); credit != "REUTERS/Kim Kyung-Hoon" {
+		t.Errorf("expected the agency and photographer, got %q", credit)
+	}
+	if credit := ArcImageCredit(map[string]any{"byline": " Jane Doe "}); credit != "Jane Doe" {
+		t.Errorf("expected the byline, got %q", credit)
+	}
+	if credit := ArcImageCredit(map[string]any{}); credit != "" {
+		t.Errorf("expected no credit, got %q", credit)
+	}
+}
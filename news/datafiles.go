@@ -0,0 +1,70 @@
+package news
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// dataFile is the contents of an editable data file, either embedded in the binary or read from an override
+// directory.
+type dataFile struct {
+	path string
+	data []byte
+}
+
+// readDataFiles reads the embedded files matching pattern, sorted by name. If dir is not empty, any file in it with
+// the same extension replaces the embedded file of the same name.
+func readDataFiles(embedded embed.FS, pattern string, dir string) ([]dataFile, error) {
+	// Maps the file name to its path and whether it comes from the embedded data.
+	type source struct {
+		path     string
+		embedded bool
+	}
+	sources := make(map[string]source)
+
+	paths, err := fs.Glob(embedded, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		sources[filepath.Base(path)] = source{path, true}
+	}
+
+	if dir != "" {
+		overrides, err := filepath.Glob(filepath.Join(dir, "*"+filepath.Ext(pattern)))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range overrides {
+			sources[filepath.Base(path)] = source{path, false}
+		}
+	}
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var files []dataFile
+	for _, name := range names {
+		src := sources[name]
+
+		var data []byte
+		if src.embedded {
+			data, err = embedded.ReadFile(src.path)
+		} else {
+			data, err = os.ReadFile(src.path)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, dataFile{src.path, data})
+	}
+
+	return files, nil
+}
@@ -0,0 +1,388 @@
+package news
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// DuplicateThresholds is the similarity from which two articles are considered the same story, per language.
+// Japanese is compared on character pairs, which differ more between rewordings of the same headline.
+var DuplicateThresholds = map[string]float64{
+	"en": 0.55,
+	"de": 0.55,
+	"es": 0.55,
+	"fr": 0.55,
+	"it": 0.55,
+	"nl": 0.55,
+	"ja": 0.45,
+}
+
+const defaultDuplicateThreshold = 0.55
+
+const (
+	// The signature is split in bands, and documents sharing any band are compared.
+	// With two rows per band, pairs with a similarity of 0.4 are found more than 99% of the time.
+	minHashBands = 32
+	minHashRows  = 2
+	minHashSize  = minHashBands * minHashRows
+)
+
+// DuplicateMatch is a previous article that a candidate was found to duplicate.
+type DuplicateMatch struct {
+	Title string
+	Score float64
+}
+
+// Deduplicator finds articles that tell the same story as one we already used.
+// Articles are compared using word shingles of the title and lead paragraph, with candidates found through MinHash LSH,
+// so a check doesn't have to go through every article of the last day.
+type Deduplicator struct {
+	language  string
+	threshold float64
+	documents []dedupDocument
+	buckets   map[uint64][]int
+}
+
+type dedupDocument struct {
+	title   string
+	titles  map[string]bool
+	content map[string]bool
+}
+
+// NewDeduplicator creates a Deduplicator for articles in the given language.
+func NewDeduplicator(language string) *Deduplicator {
+	threshold, ok := DuplicateThresholds[language]
+	if !ok {
+		threshold = defaultDuplicateThreshold
+	}
+
+	return &Deduplicator{
+		language:  language,
+		threshold: threshold,
+		buckets:   make(map[uint64][]int),
+	}
+}
+
+// Add remembers an article so that following duplicates of it are found. The lead may be empty.
+func (d *Deduplicator) Add(title string, lead string) {
+	document := d.makeDocument(title, lead)
+	index := len(d.documents)
+	d.documents = append(d.documents, document)
+
+	for _, band := range bands(document.titles) {
+		d.buckets[band] = append(d.buckets[band], index)
+	}
+	if document.content != nil {
+		for _, band := range bands(document.content) {
+			d.buckets[band] = append(d.buckets[band], index)
+		}
+	}
+}
+
+// Find returns the most similar previous article if it is a duplicate. The lead may be empty if it isn't known yet,
+// in which case only titles are compared.
+func (d *Deduplicator) Find(title string, lead string) (DuplicateMatch, bool) {
+	var best DuplicateMatch
+	for index, score := range d.scores(title, lead) {
+		if score > best.Score {
+			best = DuplicateMatch{
+				Title: d.documents[index].title,
+				Score: score,
+			}
+		}
+	}
+
+	return best, best.Score >= d.threshold
+}
+
+// IsDuplicate reports whether the article duplicates one that was added before.
+func (d *Deduplicator) IsDuplicate(title string, lead string) bool {
+	_, duplicate := d.Find(title, lead)
+	return duplicate
+}
+
+// matches returns the indices, in order of addition, of every previous article the article duplicates.
+func (d *Deduplicator) matches(title string, lead string) map[int]float64 {
+	matches := make(map[int]float64)
+	for index, score := range d.scores(title, lead) {
+		if score >= d.threshold {
+			matches[index] = score
+		}
+	}
+
+	return matches
+}
+
+// scores returns the similarity of the article with every previous article sharing an LSH band with it.
+func (d *Deduplicator) scores(title string, lead string) map[int]float64 {
+	document := d.makeDocument(title, lead)
+
+	candidates := make(map[int]bool)
+	for _, band := range bands(document.titles) {
+		for _, index := range d.buckets[band] {
+			candidates[index] = true
+		}
+	}
+	if document.content != nil {
+		for _, band := range bands(document.content) {
+			for _, index := range d.buckets[band] {
+				candidates[index] = true
+			}
+		}
+	}
+
+	scores := make(map[int]float64)
+	for index := range candidates {
+		previous := d.documents[index]
+
+		score := jaccard(document.titles, previous.titles)
+		if document.content != nil && previous.content != nil {
+			score = max(score, jaccard(document.content, previous.content))
+		}
+
+		scores[index] = score
+	}
+
+	return scores
+}
+
+// similarity compares two articles that were added, in the same way as scores.
+func (d *Deduplicator) similarity(i int, j int) float64 {
+	a, b := d.documents[i], d.documents[j]
+
+	score := jaccard(a.titles, b.titles)
+	if a.content != nil && b.content != nil {
+		score = max(score, jaccard(a.content, b.content))
+	}
+
+	return score
+}
+
+func (d *Deduplicator) makeDocument(title string, lead string) dedupDocument {
+	titleTokens := Tokenize(title, d.language)
+	document := dedupDocument{
+		title:  title,
+		titles: shingles(titleTokens),
+	}
+
+	if lead != "" {
+		document.content = shingles(append(titleTokens, Tokenize(lead, d.language)...))
+	}
+
+	return document
+}
+
+// Lead returns the first paragraph of an article's content.
+func Lead(content string) string {
+	lead, _, _ := strings.Cut(strings.TrimSpace(content), "\n\n")
+	return lead
+}
+
+// Tokenize splits text into normalized words. Scripts written without spaces are split into overlapping character
+// pairs instead, which is the usual way to index Japanese without a dictionary.
+func Tokenize(text string, language string) []string {
+	text = norm.NFKC.String(text)
+	stopWords := stopWordsByLanguage[language]
+
+	var tokens []string
+	var word []rune
+	var run []rune
+
+	flushWord := func() {
+		if len(word) == 0 {
+			return
+		}
+
+		token := foldWord(string(word))
+		word = word[:0]
+		if !stopWords[token] {
+			tokens = append(tokens, stem(token, language))
+		}
+	}
+
+	flushRun := func() {
+		if len(run) == 1 {
+			tokens = append(tokens, string(run))
+		}
+		for i := 0; i+1 < len(run); i++ {
+			tokens = append(tokens, string(run[i:i+2]))
+		}
+		run = run[:0]
+	}
+
+	for _, r := range text {
+		if isCJK(r) {
+			flushWord()
+			run = append(run, r)
+			continue
+		}
+		flushRun()
+
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r):
+			word = append(word, r)
+		case (r == '\'' || r == '’') && elidingLanguages[language] && len(word) <= 4:
+			// Elided articles such as in "l'Europe" or "dell'Italia" are dropped.
+			word = word[:0]
+		default:
+			flushWord()
+		}
+	}
+	flushWord()
+	flushRun()
+
+	return tokens
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) || r == 'ー'
+}
+
+// foldWord lower cases a word and removes its accents, as feeds aren't consistent about them in titles.
+func foldWord(word string) string {
+	var builder strings.Builder
+	for _, r := range norm.NFD.String(word) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+
+	return builder.String()
+}
+
+// stem removes the most common inflections of a word, so "neue" and "neues" or "incontra" and "incontro" match.
+// This is far from a proper stemmer, but only has to be consistent between two headlines.
+func stem(word string, language string) string {
+	if len([]rune(word)) <= 3 {
+		return word
+	}
+
+	for _, suffix := range suffixesByLanguage[language] {
+		if strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+
+	return word
+}
+
+// shingles returns the set of single words and pairs of consecutive words.
+// Pairs keep word order significant, so headlines with the same words in a different order don't match.
+func shingles(tokens []string) map[string]bool {
+	set := make(map[string]bool)
+	for i, token := range tokens {
+		set[token] = true
+		if i+1 < len(tokens) {
+			set[token+" "+tokens[i+1]] = true
+		}
+	}
+
+	return set
+}
+
+func jaccard(a map[string]bool, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	intersection := 0
+	for shingle := range a {
+		if b[shingle] {
+			intersection++
+		}
+	}
+
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
+
+// bands returns the LSH band hashes of the MinHash signature of a set of shingles.
+func bands(set map[string]bool) []uint64 {
+	if len(set) == 0 {
+		return nil
+	}
+
+	var signature [minHashSize]uint64
+	for i := range signature {
+		signature[i] = ^uint64(0)
+	}
+
+	for shingle := range set {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(shingle))
+		base := h.Sum64()
+
+		for i := range signature {
+			// Derive the hash functions from a single hash by mixing in the function index.
+			value := mix(base ^ (uint64(i+1) * 0x9e3779b97f4a7c15))
+			if value < signature[i] {
+				signature[i] = value
+			}
+		}
+	}
+
+	result := make([]uint64, minHashBands)
+	for band := range result {
+		hash := uint64(band)
+		for row := 0; row < minHashRows; row++ {
+			hash = mix(hash ^ signature[band*minHashRows+row])
+		}
+		result[band] = hash
+	}
+
+	return result
+}
+
+// mix is the SplitMix64 finalizer.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// elidingLanguages are the languages where articles and prepositions are joined to the next word with an apostrophe.
+var elidingLanguages = map[string]bool{
+	"fr": true,
+	"it": true,
+}
+
+// suffixesByLanguage are the inflection suffixes removed by stem, longest first.
+var suffixesByLanguage = map[string][]string{
+	"en": {"ing", "ed", "s"},
+	"de": {"en", "es", "er", "em", "e", "s", "n"},
+	"es": {"es", "s"},
+	"fr": {"es", "s", "e"},
+	"it": {"a", "e", "i", "o"},
+	"nl": {"en", "e", "s"},
+}
+
+// stopWordsByLanguage contains words that appear in most headlines and say nothing about the story.
+var stopWordsByLanguage = map[string]map[string]bool{
+	"en": makeSet("a", "an", "the", "to", "of", "in", "on", "at", "for", "and", "or", "is", "are", "was", "were", "be",
+		"will", "as", "by", "with", "from", "after", "over", "its", "it", "that", "this", "has", "have", "says", "say", "s", "t"),
+	"de": makeSet("der", "die", "das", "den", "dem", "des", "ein", "eine", "einen", "einem", "und", "oder", "in", "im",
+		"auf", "an", "am", "zu", "zum", "zur", "von", "vom", "mit", "für", "fur", "ist", "sind", "wird", "nach", "bei", "aus"),
+	"es": makeSet("el", "la", "los", "las", "un", "una", "de", "del", "en", "y", "o", "a", "al", "por", "para", "con",
+		"que", "se", "su", "sus", "es", "lo", "tras"),
+	"fr": makeSet("le", "la", "les", "un", "une", "des", "de", "du", "en", "et", "ou", "a", "au", "aux", "pour", "par",
+		"avec", "sur", "dans", "que", "qui", "se", "sa", "son", "ses", "est", "apres"),
+	"it": makeSet("il", "lo", "la", "i", "gli", "le", "un", "uno", "una", "di", "del", "della", "dei", "delle", "in",
+		"nel", "nella", "e", "o", "a", "al", "alla", "per", "con", "su", "sul", "che", "si", "da", "dopo"),
+	"nl": makeSet("de", "het", "een", "en", "of", "in", "op", "aan", "van", "voor", "met", "is", "zijn", "wordt",
+		"na", "bij", "uit", "om", "te", "naar", "door"),
+}
+
+func makeSet(words ...string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range words {
+		set[word] = true
+	}
+
+	return set
+}
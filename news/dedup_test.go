@@ -0,0 +1,72 @@
+package news
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+type duplicatePair struct {
+	Language  string `json:"language"`
+	A         string `json:"a"`
+	LeadA     string `json:"leadA"`
+	B         string `json:"b"`
+	LeadB     string `json:"leadB"`
+	Duplicate bool   `json:"duplicate"`
+}
+
+func TestDuplicateCorpus(t *testing.T) {
+	data, err := os.ReadFile("testdata/duplicates.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pairs []duplicatePair
+	err = json.Unmarshal(data, &pairs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pair := range pairs {
+		dedup := NewDeduplicator(pair.Language)
+		dedup.Add(pair.A, pair.LeadA)
+
+		match, duplicate := dedup.Find(pair.B, pair.LeadB)
+		if duplicate != pair.Duplicate {
+			t.Errorf("%q and %q: expected duplicate to be %v, got %v with a score of %.2f",
+				pair.A, pair.B, pair.Duplicate, duplicate, match.Score)
+		}
+	}
+}
+
+func TestDuplicateAmongMany(t *testing.T) {
+	dedup := NewDeduplicator("en")
+	dedup.Add("Oil prices rise as Middle East tensions grow", "")
+	dedup.Add("Biden to visit Japan next week for talks with Kishida", "")
+	dedup.Add("Apple unveils new iPhone with faster chip", "")
+
+	match, duplicate := dedup.Find("Biden will visit Japan next week for talks with Kishida", "")
+	if !duplicate || match.Title != "Biden to visit Japan next week for talks with Kishida" {
+		t.Errorf("expected a match with the Biden article, got %+v", match)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text     string
+		language string
+		expected []string
+	}{
+		{"The Fed's rates", "en", []string{"fed", "rate"}},
+		{"L'Élysée répond", "fr", []string{"elyse", "repond"}},
+		{"日銀、決定", "ja", []string{"日銀", "決定"}},
+		{"東京ＡＢＣ株式", "ja", []string{"東京", "abc", "株式"}},
+	}
+
+	for _, test := range tests {
+		if actual := Tokenize(test.text, test.language); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Tokenize(%q): expected %q, got %q", test.text, test.expected, actual)
+		}
+	}
+}
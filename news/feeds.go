@@ -0,0 +1,155 @@
+package news
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// feedsVersion is the newest version of the feeds format we understand.
+const feedsVersion = 1
+
+// Feeds are the feeds a source reads besides its built-in ones, each filing its articles under a topic. They let
+// countries show topics that no built-in feed fills, such as weather or health.
+type Feeds struct {
+	Version int    `json:"version"`
+	Source  string `json:"source"`
+	Feeds   []Feed `json:"feeds"`
+
+	// Where the file was read from, used for reporting.
+	path string
+}
+
+// Feed is the URL of a feed in the format the source reads, and the topic of its articles.
+type Feed struct {
+	Topic Topic  `json:"topic"`
+	URL   string `json:"url"`
+}
+
+//go:embed data/feeds/*.json
+var feedsFS embed.FS
+
+// feeds maps source names to their additional feeds.
+var feeds = map[string][]Feed{}
+
+func init() {
+	// The embedded feeds are validated by the tests, so failing here means the binary itself is broken.
+	err := LoadFeeds("")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// LoadFeeds loads the additional feeds of every source from the embedded files.
+// If dir is not empty, any <source>.json file inside it replaces the embedded feeds of that source.
+func LoadFeeds(dir string) error {
+	files, err := ReadFeeds(dir)
+	if err != nil {
+		return err
+	}
+
+	loaded := make(map[string][]Feed)
+	for _, file := range files {
+		if file.Version > feedsVersion {
+			return fmt.Errorf("%s: unsupported feeds version %d", file.path, file.Version)
+		}
+
+		loaded[file.Source] = file.Feeds
+	}
+
+	feeds = loaded
+	return nil
+}
+
+// ReadFeeds reads the additional feeds of every source, preferring the files found in dir over the embedded copies.
+func ReadFeeds(dir string) ([]*Feeds, error) {
+	sources, err := readDataFiles(feedsFS, "data/feeds/*.json", dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*Feeds
+	for _, src := range sources {
+		file := &Feeds{path: src.path}
+		err = json.Unmarshal(src.data, file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src.path, err)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// ValidateFeeds checks the feeds that LoadFeeds would use and returns every problem found.
+func ValidateFeeds(dir string) []error {
+	files, err := ReadFeeds(dir)
+	if err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+	report := func(file *Feeds, format string, args ...any) {
+		problems = append(problems, fmt.Errorf("%s: %s", file.path, fmt.Sprintf(format, args...)))
+	}
+
+	for _, file := range files {
+		if file.Version < 1 || file.Version > feedsVersion {
+			report(file, "unsupported version %d", file.Version)
+		}
+
+		source := strings.TrimSuffix(filepath.Base(file.path), ".json")
+		if file.Source != source {
+			report(file, "source %q does not match the file name", file.Source)
+		}
+
+		for _, feed := range file.Feeds {
+			if feed.Topic == "" {
+				report(file, "feed %q has no topic", feed.URL)
+			}
+			if address, err := url.Parse(feed.URL); err != nil || !address.IsAbs() {
+				report(file, "feed URL %q of topic %q is not absolute", feed.URL, feed.Topic)
+			}
+		}
+	}
+
+	return problems
+}
+
+// FeedsFor returns the additional feeds of a source, which its GetArticles reads after the built-in ones.
+func FeedsFor(source string) []Feed {
+	return feeds[source]
+}
+
+// SourceTopics returns the topics a source's articles can be filed under: the built-in topics, which every source
+// fills, followed by those of its additional feeds.
+func SourceTopics(source string) []Topic {
+	topics := slices.Clone(BuiltinTopics)
+	for _, feed := range feeds[source] {
+		if !slices.Contains(topics, feed.Topic) {
+			topics = append(topics, feed.Topic)
+		}
+	}
+
+	return topics
+}
+
+// KnownTopic reports whether any source can fill a topic.
+func KnownTopic(topic Topic) bool {
+	if slices.Contains(BuiltinTopics, topic) {
+		return true
+	}
+
+	for _, sourceFeeds := range feeds {
+		if slices.ContainsFunc(sourceFeeds, func(feed Feed) bool { return feed.Topic == topic }) {
+			return true
+		}
+	}
+
+	return false
+}
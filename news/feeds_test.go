@@ -0,0 +1,65 @@
+package news
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestEmbeddedFeedsAreValid(t *testing.T) {
+	for _, problem := range ValidateFeeds("") {
+		t.Error(problem)
+	}
+}
+
+func TestFeedsOverride(t *testing.T) {
+	dir := t.TempDir()
+	data := `{
+  "version": 1,
+  "source": "ap",
+  "feeds": [{"topic": "weather", "url": "/rss/weather"}, {"url": "https://example.com/rss/health"}]
+}`
+	err := os.WriteFile(filepath.Join(dir, "nos.json"), []byte(data), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Source not matching the file name, relative URL and missing topic.
+	problems := ValidateFeeds(dir)
+	if len(problems) != 3 {
+		t.Errorf("expected 3 problems, got %d: %v", len(problems), problems)
+	}
+
+	t.Cleanup(func() {
+		_ = LoadFeeds("")
+	})
+
+	data = `{"version": 1, "source": "nos", "feeds": [{"topic": "weather", "url": "https://example.com/rss/weather"}]}`
+	err = os.WriteFile(filepath.Join(dir, "nos.json"), []byte(data), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = LoadFeeds(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if feeds := FeedsFor("nos"); len(feeds) != 1 || feeds[0].Topic != "weather" {
+		t.Errorf("expected the weather feed, got %v", feeds)
+	}
+	if expected := append(slices.Clone(BuiltinTopics), "weather"); !slices.Equal(SourceTopics("nos"), expected) {
+		t.Errorf("expected %v, got %v", expected, SourceTopics("nos"))
+	}
+	if slices.Contains(SourceTopics("ap"), "weather") || !KnownTopic("weather") || KnownTopic("health") {
+		t.Error("expected only NOS to fill the weather topic")
+	}
+
+	if err = (SummaryRule{Source: "nos", Topic: "weather", Length: 500}).Validate(); err != nil {
+		t.Error(err)
+	}
+	if err = (SummaryRule{Source: "ap", Topic: "weather", Length: 500}).Validate(); err == nil {
+		t.Error("expected a topic the source doesn't fill to be rejected")
+	}
+}
@@ -43,6 +43,15 @@ func (a *france24) GetArticles() ([]news.Article, error) {
 	}
 	articles = append(articles, temp...)
 
+	// Additional feeds from the feeds data files.
+	for _, feed := range news.FeedsFor("france24") {
+		temp, err = a.getArticles(feed.URL, feed.Topic)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, temp...)
+	}
+
 	return articles, nil
 }
 
@@ -71,5 +80,6 @@ func (a *france24) GetScienceArticles() ([]news.Article, error) {
 }
 
 func (a *france24) GetTechnologyArticles() ([]news.Article, error) {
-	return a.getArticles("https://www.france24.com/fr/éco-tech/rss", news.Technology)
+	// The same feed covers science, so the classifier picks between the two.
+	return a.getArticles("https://www.france24.com/fr/éco-tech/rss", news.Technology, news.Science)
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/xml"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -38,10 +39,10 @@ type MediaThumbnail struct {
 }
 
 type france24 struct {
-	oldArticleTitles []string
+	dedup *news.Deduplicator
 }
 
-func (a *france24) getArticles(url string, topic news.Topic) ([]news.Article, error) {
+func (a *france24) getArticles(url string, topic news.Topic, alternatives ...news.Topic) ([]news.Article, error) {
 	// Fetch RSS XML
 	data, err := news.HttpGet(url)
 	if err != nil {
@@ -63,49 +64,63 @@ func (a *france24) getArticles(url string, topic news.Topic) ([]news.Article, er
 
 		title := news.SanitizeText(item.Title)
 		// Check for duplicates
-		if news.IsDuplicateArticle(a.oldArticleTitles, title) {
+		skip := news.SkipEvent{
+			Source: "france24",
+			Topic:  topic.String(),
+			URL:    item.Link,
+			Title:  title,
+		}
+		if match, duplicate := a.dedup.Find(title, news.SanitizeText(item.Description)); duplicate {
+			skip.Reason, skip.MatchedTitle, skip.Score = news.SkipDuplicate, match.Title, match.Score
+			news.ReportSkip(skip)
 			continue
 		}
-		a.oldArticleTitles = append(a.oldArticleTitles, title)
 
 		// Get full article content by scraping the link
-		content, location, thumbnail, err := a.getFullArticle(item.Link)
+		body, location, html, err := a.getFullArticle(item.Link)
 		if err != nil {
 			return nil, err
 		}
 
-		contentString := *content
-
 		// Use description as fallback if content fetch fails
-		if contentString == "" {
-			contentString = item.Description
+		if len(body) == 0 {
+			body = news.BoilerplateFor("france24").ParseBodyHTML(item.Description)
 		}
-
-		// Clean HTML tags from content
-		contentString = news.SanitizeText(contentString)
+		content := body.Render()
 
 		// Skip if no content
-		if len(strings.TrimSpace(contentString)) == 0 {
+		if len(content) == 0 {
+			skip.Reason = news.SkipNoContent
+			news.ReportSkip(skip)
 			continue
 		}
+		a.dedup.Add(title, news.Lead(content))
+
+		// Pictures are only fetched once we know the article is used.
+		pictures := a.extractPictures(html, item.Link)
 
 		// Use media thumbnail if available
-		if thumbnail == nil && item.MediaThumbnail.URL != "" {
-			imageData, err := news.HttpGet(item.MediaThumbnail.URL)
-			if err == nil && len(imageData) > 0 {
-				thumbnail = &news.Thumbnail{
-					Image:   news.ConvertImage(imageData),
+		if pictures == nil && item.MediaThumbnail.URL != "" {
+			imageData := news.ConvertFirstImage([]string{item.MediaThumbnail.URL})
+			if imageData != nil {
+				pictures = []news.Thumbnail{{
+					Image:   imageData,
 					Caption: "",
-				}
+				}}
 			}
 		}
 
 		article := news.Article{
-			Title:     title,
-			Content:   &contentString,
-			Topic:     topic,
-			Location:  location,
-			Thumbnail: thumbnail,
+			Title:             title,
+			Source:            skip.Source,
+			URL:               skip.URL,
+			Content:           &content,
+			Body:              body,
+			Topic:             topic,
+			AlternativeTopics: alternatives,
+			Location:          location,
+			Pictures:          pictures,
+			PublishedTime:     news.ParseTime(item.PubDate, time.RFC1123Z, time.RFC1123),
 		}
 
 		articles = append(articles, article)
@@ -114,49 +129,38 @@ func (a *france24) getArticles(url string, topic news.Topic) ([]news.Article, er
 	return articles, nil
 }
 
-func (a *france24) getFullArticle(articleURL string) (*string, *news.Location, *news.Thumbnail, error) {
+// getFullArticle returns the content and location of an article, along with its page to find the thumbnail in.
+func (a *france24) getFullArticle(articleURL string) (news.Body, *news.Location, string, error) {
 	if articleURL == "" {
-		return nil, nil, nil, nil
+		return nil, nil, "", nil
 	}
 
 	data, err := news.HttpGet(articleURL)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, "", err
 	}
 
 	html := string(data)
 
-	content, err := a.extractArticleBody(html)
+	body, err := a.extractArticleBody(html)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, "", err
 	}
 	location := a.extractLocationFromContent(html)
-	thumbnail := a.extractThumbnail(html)
 
-	return content, location, thumbnail, nil
+	return body, location, html, nil
 }
 
-func (a *france24) extractArticleBody(html string) (*string, error) {
+func (a *france24) extractArticleBody(html string) (news.Body, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		return nil, err
 	}
 
-	var ret string
-
 	// Find article body section
-	doc.Find(`div.t-content__body`).Each(func(i int, section *goquery.Selection) {
-		section.Find(`p.a-read-more`).Remove()
-		section.Find("p").Each(func(j int, elem *goquery.Selection) {
-			text := strings.TrimSpace(elem.Text())
-			if text != "" {
-				ret += text
-				ret += "\n\n"
-			}
-		})
-	})
+	section := doc.Find(`div.t-content__body`)
 
-	return &ret, nil
+	return news.BoilerplateFor("france24").ParseBody(section.Find("p, h2, h3, ul")), nil
 }
 
 func (a *france24) extractLocationFromContent(html string) *news.Location {
@@ -199,56 +203,26 @@ func (a *france24) extractLocationFromContent(html string) *news.Location {
 	return news.GetLocationForExtractedLocation(candidates, "fr")
 }
 
-func (a *france24) extractThumbnail(html string) *news.Thumbnail {
+func (a *france24) extractPictures(html string, articleURL string) []news.Thumbnail {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		log.Println("Failed to parse HTML:", err)
 		return nil
 	}
 
-	var imageURL string
-
-	doc.Find(`meta[property="og:image"]`).EachWithBreak(func(i int, s *goquery.Selection) bool {
-		if content, exists := s.Attr("content"); exists && strings.TrimSpace(content) != "" {
-			imageURL = content
-			return false
-		}
-		return true
-	})
-
-	if imageURL == "" {
-		return nil
-	}
-
-	// Make sure URL is absolute
-	if strings.HasPrefix(imageURL, "//") {
-		imageURL = "https:" + imageURL
-	} else if strings.HasPrefix(imageURL, "/") {
-		imageURL = "https://www.france24.com" + imageURL
-	}
-
-	imageData, err := news.HttpGet(imageURL)
-	if err != nil || len(imageData) == 0 {
+	// Try the other renditions of the page if the shared picture can't be used.
+	image, imageURL := news.ConvertFirstCandidate(news.ImageCandidates(doc, articleURL))
+	if image == nil {
 		return nil
 	}
 
-	caption := ""
-	doc.Find("figure.m-item-image figcaption.a-figcaption").Each(func(i int, s *goquery.Selection) {
-		var captionParts []string
-		s.Find("span").Each(func(j int, span *goquery.Selection) {
-			text := strings.TrimSpace(span.Text())
-			if text != "" {
-				captionParts = append(captionParts, text)
-			}
-		})
-		if len(captionParts) > 0 {
-			caption = strings.Join(captionParts, " ")
-			return
-		}
-	})
+	// The credit has its own element in the caption.
+	caption, credit := news.FigureCaptionCredit(doc.Find("figure.m-item-image").First())
 
-	return &news.Thumbnail{
-		Image:   news.ConvertImage(imageData),
-		Caption: news.SanitizeText(caption),
-	}
+	pictures := []news.Thumbnail{{
+		Image:   image,
+		Caption: caption,
+		Credit:  credit,
+	}}
+	return append(pictures, news.GalleryFallbacks(doc, articleURL, imageURL, news.PictureFallbacks)...)
 }
@@ -1,6 +1,7 @@
 package france24
 
 import (
+	"NewsChannel/news"
 	_ "embed"
 	"fmt"
 	"strconv"
@@ -11,9 +12,9 @@ import (
 //go:embed logo.jpg
 var Logo []byte
 
-func NewFrance24(oldArticleTitles []string) *france24 {
+func NewFrance24(dedup *news.Deduplicator) *france24 {
 	return &france24{
-		oldArticleTitles: oldArticleTitles,
+		dedup: dedup,
 	}
 }
 
@@ -0,0 +1,244 @@
+package news
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// GlyphCoverage is the set of characters the console's font can show in a language.
+// Anything else renders as a box on the Wii.
+type GlyphCoverage struct {
+	charset encoding.Encoding
+	// missing stands in for characters that have no fallback.
+	missing string
+}
+
+var (
+	westernCoverage  = &GlyphCoverage{charmap.Windows1252, "?"}
+	japaneseCoverage = &GlyphCoverage{japanese.ShiftJIS, "〓"}
+)
+
+// CoverageForLanguage returns the coverage of the font used for a language tag. The Japanese font covers
+// JIS X 0208, the font of the other languages Windows-1252.
+func CoverageForLanguage(language string) *GlyphCoverage {
+	if language == "ja" {
+		return japaneseCoverage
+	}
+
+	return westernCoverage
+}
+
+// Covers reports whether the font can show r.
+func (c *GlyphCoverage) Covers(r rune) bool {
+	if r == '\n' {
+		return true
+	}
+	if unicode.IsControl(r) {
+		return false
+	}
+	if r < utf8.RuneSelf {
+		return true
+	}
+
+	_, err := c.charset.NewEncoder().String(string(r))
+	return err == nil
+}
+
+func (c *GlyphCoverage) coversAll(text string) bool {
+	for _, r := range text {
+		if !c.Covers(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// glyphFallbacks are used for characters the font can't show when it can show their fallback.
+var glyphFallbacks = map[rune]string{
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '―': "-", '−': "-",
+	'‘': "'", '’': "'", '‚': ",", '‛': "'", '′': "'", '“': "\"", '”': "\"", '„': "\"", '‟': "\"", '″': "\"",
+	'‹': "<", '›': ">", '«': "\"", '»': "\"", '…': "...", '•': "・", '⁄': "/",
+	'≤': "<=", '≥': ">=", '≠': "!=", '≈': "~", '→': "->", '←': "<-", '×': "x", '÷': "/",
+	'½': "1/2", '¼': "1/4", '¾': "3/4", '⅓': "1/3", '⅔': "2/3",
+	'€': "EUR", '™': "(TM)", '©': "(C)", '®': "(R)",
+	'ß': "ss", 'ẞ': "SS", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE", 'ø': "o", 'Ø': "O",
+	'ł': "l", 'Ł': "L", 'đ': "d", 'Đ': "D", 'ħ': "h", 'Ħ': "H", 'ı': "i", 'ŋ': "ng", 'Ŋ': "Ng",
+	'þ': "th", 'Þ': "Th", 'ð': "d", 'Ð': "D", 'ə': "e", 'Ə': "E",
+	'\u2028': "\n", '\u2029': "\n",
+}
+
+// transliterations spell lowercase Cyrillic and Greek letters with Latin ones.
+var transliterations = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e", 'ё': "yo", 'є': "ye", 'ж': "zh",
+	'з': "z", 'и': "i", 'і': "i", 'ї': "yi", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh",
+	'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i", 'κ': "k",
+	'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t",
+	'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// transliterate returns the Latin spelling of a Cyrillic or Greek letter.
+func transliterate(r rune) (string, bool) {
+	lower := unicode.ToLower(r)
+	latin, ok := transliterations[lower]
+	if !ok {
+		// Accented Greek letters are looked up without their accent.
+		lower = []rune(norm.NFD.String(string(lower)))[0]
+		latin, ok = transliterations[lower]
+	}
+	if !ok || latin == "" || unicode.IsLower(r) {
+		return latin, ok
+	}
+
+	first, size := utf8.DecodeRuneInString(latin)
+	return string(unicode.ToUpper(first)) + latin[size:], true
+}
+
+// fallback returns what is shown instead of r, which the font can't show. It is empty for characters that are
+// dropped, such as emoji and directional marks.
+func (c *GlyphCoverage) fallback(r rune) string {
+	if fallback, ok := glyphFallbacks[r]; ok && c.coversAll(fallback) {
+		return fallback
+	}
+
+	if unicode.Is(unicode.Zs, r) {
+		return " "
+	}
+
+	// Invisible formatting, such as directional marks, zero width joiners and variation selectors.
+	if unicode.In(r, unicode.Cf, unicode.Mn, unicode.Me, unicode.Cc) {
+		return ""
+	}
+
+	// Accented letters lose their accent, and compatibility characters such as ligatures and full width letters
+	// become the characters they are made of.
+	var decomposed strings.Builder
+	for _, d := range norm.NFKD.String(string(r)) {
+		if !unicode.Is(unicode.Mn, d) {
+			decomposed.WriteRune(d)
+		}
+	}
+	if decomposed.Len() != 0 && c.coversAll(decomposed.String()) {
+		return decomposed.String()
+	}
+
+	if wide := width.Widen.String(string(r)); c.coversAll(wide) {
+		return wide
+	}
+
+	if latin, ok := transliterate(r); ok && c.coversAll(latin) {
+		return latin
+	}
+
+	// Emoji, pictographs and private use characters have nothing sensible to show.
+	if unicode.In(r, unicode.So, unicode.Sk, unicode.Co, unicode.Cs) {
+		return ""
+	}
+
+	return c.missing
+}
+
+// Replacement is a character the font can't show, and what replaced it.
+type Replacement struct {
+	Original    rune
+	Replacement string
+	Count       int
+}
+
+func (r Replacement) String() string {
+	return fmt.Sprintf("%U %q → %q (%d)", r.Original, r.Original, r.Replacement, r.Count)
+}
+
+func addReplacement(replacements []Replacement, replacement Replacement) []Replacement {
+	for i := range replacements {
+		if replacements[i].Original == replacement.Original {
+			replacements[i].Count += replacement.Count
+			return replacements
+		}
+	}
+
+	return append(replacements, replacement)
+}
+
+var (
+	repeatedSpaces    = regexp.MustCompile(` {2,}`)
+	spacesAtLineBreak = regexp.MustCompile(` *\n *`)
+)
+
+// Normalize returns text in Unicode NFC using only characters the font can show, along with the replacements made.
+func (c *GlyphCoverage) Normalize(text string) (string, []Replacement) {
+	text = norm.NFC.String(text)
+
+	var builder strings.Builder
+	var replacements []Replacement
+	dropped := false
+	for _, r := range text {
+		if c.Covers(r) {
+			builder.WriteRune(r)
+			continue
+		}
+
+		fallback := c.fallback(r)
+		builder.WriteString(fallback)
+		replacements = addReplacement(replacements, Replacement{r, fallback, 1})
+		dropped = dropped || fallback == ""
+	}
+
+	normalized := builder.String()
+	if dropped {
+		// Don't leave the spaces around dropped characters behind.
+		normalized = repeatedSpaces.ReplaceAllString(normalized, " ")
+		normalized = spacesAtLineBreak.ReplaceAllString(normalized, "\n")
+		normalized = strings.TrimSpace(normalized)
+	}
+
+	return normalized, replacements
+}
+
+// NormalizeArticle normalizes the text of an article and of its pictures, and returns the replacements made.
+func (c *GlyphCoverage) NormalizeArticle(article *Article) []Replacement {
+	var replacements []Replacement
+	normalize := func(text string) string {
+		normalized, made := c.Normalize(text)
+		for _, replacement := range made {
+			replacements = addReplacement(replacements, replacement)
+		}
+		return normalized
+	}
+
+	article.Title = normalize(article.Title)
+	if article.Content != nil {
+		content := normalize(*article.Content)
+		article.Content = &content
+	}
+
+	// Locations and pictures can be shared with other articles, so they are copied rather than changed.
+	if article.Location != nil {
+		location := *article.Location
+		location.Name = normalize(location.Name)
+		article.Location = &location
+	}
+
+	if article.Pictures != nil {
+		pictures := make([]Thumbnail, len(article.Pictures))
+		for i, picture := range article.Pictures {
+			picture.Caption = normalize(picture.Caption)
+			picture.Credit = normalize(picture.Credit)
+			pictures[i] = picture
+		}
+		article.Pictures = pictures
+	}
+
+	return replacements
+}
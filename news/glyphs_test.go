@@ -0,0 +1,71 @@
+package news
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		language string
+		text     string
+		expected string
+	}{
+		// Characters in the font are kept, after composing them.
+		{"en", "Café – “Zürich” … 50 €", "Café – “Zürich” … 50 €"},
+		{"en", "Cafe\u0301", "Café"},
+		{"ja", "東京で会見「新しい方針」", "東京で会見「新しい方針」"},
+		// Emoji and directional marks are dropped without leaving spaces behind.
+		{"en", "Zeitung 🗞️ über Straßenbahn 🚋\nNext", "Zeitung über Straßenbahn\nNext"},
+		{"de", "‎القاهرة‏", "???????"},
+		// Letters outside of the font lose their accent, or are spelled with the closest ones.
+		{"fr", "Łódź, Kraków ≥ 3 ﬁles", "Lódz, Kraków >= 3 files"},
+		{"en", "Владимир Зеленский in Αθήνα", "Vladimir Zelenskiy in Athina"},
+		// Characters without a fallback are marked as missing.
+		{"ja", "𠮷野家で😀食事", "〓野家で食事"},
+		{"ja", "Café ½", "Cafe 1/2"},
+		{"es", "中国", "??"},
+	}
+
+	for _, test := range tests {
+		actual, _ := CoverageForLanguage(test.language).Normalize(test.text)
+		if actual != test.expected {
+			t.Errorf("%s %q: expected %q, got %q", test.language, test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestNormalizeArticle(t *testing.T) {
+	content := "Der 🚋 fährt. Noch ein 🚋."
+	location := &Location{Name: "Gdańsk"}
+	pictures := []Thumbnail{{Caption: "Eine 🚋", Credit: "Jan Łukasz"}}
+	article := Article{
+		Title:    "Neue 🚋 für Łódź",
+		Content:  &content,
+		Location: location,
+		Pictures: pictures,
+	}
+
+	replacements := CoverageForLanguage("de").NormalizeArticle(&article)
+	expected := []Replacement{
+		{'🚋', "", 4},
+		{'Ł', "L", 2},
+		{'ź', "z", 1},
+		{'ń', "n", 1},
+	}
+	if !reflect.DeepEqual(replacements, expected) {
+		t.Errorf("expected %v, got %v", expected, replacements)
+	}
+
+	if article.Title != "Neue für Lódz" || *article.Content != "Der fährt. Noch ein ." {
+		t.Errorf("unexpected text %q: %q", article.Title, *article.Content)
+	}
+	if article.Location.Name != "Gdansk" || article.Pictures[0].Caption != "Eine" || article.Pictures[0].Credit != "Jan Lukasz" {
+		t.Errorf("unexpected location %q or picture %+v", article.Location.Name, article.Pictures[0])
+	}
+
+	// Shared data is left alone.
+	if location.Name != "Gdańsk" || pictures[0].Caption != "Eine 🚋" || content != "Der 🚋 fährt. Noch ein 🚋." {
+		t.Error("expected the original location, pictures and content to be unchanged")
+	}
+}
@@ -0,0 +1,286 @@
+package news
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// conversionErrorTTL is how long a picture that couldn't be converted isn't tried again.
+const conversionErrorTTL = 24 * time.Hour
+
+// imagePipelineVersion is part of the key of converted pictures. Bump it whenever the conversion code changes,
+// so that pictures converted by an older version are redone.
+const imagePipelineVersion = 1
+
+// ImageCache keeps downloaded pictures and their conversions on disk between runs, as most articles keep their
+// picture for hours. Originals are stored by content hash, along with the ETag and Last-Modified of every URL they
+// were downloaded from so that they can be revalidated. Conversions are stored by original hash and pipeline.
+type ImageCache struct {
+	dir      string
+	maxBytes int64
+}
+
+// cachedURL is what we know about the picture at a URL.
+type cachedURL struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	// Hash is the content hash of the original picture.
+	Hash string `json:"hash"`
+}
+
+// PictureCache is the cache used when fetching and converting pictures. Nil disables caching.
+var PictureCache *ImageCache
+
+// NewImageCache creates a cache in dir that is pruned down to maxBytes.
+func NewImageCache(dir string, maxBytes int64) (*ImageCache, error) {
+	for _, subdirectory := range []string{"urls", "originals", "converted"} {
+		err := os.MkdirAll(filepath.Join(dir, subdirectory), os.ModePerm)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ImageCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+	}, nil
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *ImageCache) urlPath(url string) string {
+	return filepath.Join(c.dir, "urls", hashBytes([]byte(url))+".json")
+}
+
+func (c *ImageCache) originalPath(hash string) string {
+	return filepath.Join(c.dir, "originals", hash)
+}
+
+func (c *ImageCache) convertedPath(hash string, pipeline ImagePipeline) string {
+	return filepath.Join(c.dir, "converted", hash+"_"+pipeline.version()+".jpg")
+}
+
+// Fetch returns the picture at url, downloading it only if it changed since it was cached.
+// If the source can't be reached, the cached copy is used.
+func (c *ImageCache) Fetch(url string, userAgent ...string) ([]byte, error) {
+	var cached cachedURL
+	var cachedData []byte
+	if data, err := os.ReadFile(c.urlPath(url)); err == nil && json.Unmarshal(data, &cached) == nil {
+		cachedData, _ = c.read(c.originalPath(cached.Hash))
+	}
+
+	request, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("User-Agent", "WiiLink News Channel File Generator")
+	if len(userAgent) > 0 && userAgent[0] != "" {
+		request.Header.Set("User-Agent", userAgent[0])
+	}
+	if cachedData != nil {
+		if cached.ETag != "" {
+			request.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			request.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	response, err := sendWithRetries(http.DefaultClient, request, http.StatusOK, http.StatusNotModified)
+	if err != nil {
+		if cachedData != nil {
+			log.Printf("Using cached image for %s: %v", url, err)
+			return cachedData, nil
+		}
+		return nil, fmt.Errorf("HTTP request to %v failed: %v", url, err)
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Println("error closing body:", err)
+		}
+	}(response.Body)
+
+	switch {
+	case response.StatusCode == http.StatusNotModified && cachedData != nil:
+		return cachedData, nil
+	case response.StatusCode != http.StatusOK && cachedData != nil:
+		log.Printf("Using cached image for %s: Status Code %v", url, response.StatusCode)
+		return cachedData, nil
+	case response.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("HTTP request to %v failed: Status Code %v", url, response.StatusCode)
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	cached = cachedURL{
+		URL:          url,
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+		Hash:         hashBytes(data),
+	}
+	metadata, err := json.Marshal(cached)
+	if err != nil {
+		return nil, err
+	}
+
+	// A failure to cache doesn't prevent using the picture.
+	if err = c.write(c.originalPath(cached.Hash), data); err == nil {
+		err = c.write(c.urlPath(url), metadata)
+	}
+	if err != nil {
+		log.Printf("Failed to cache image %s: %v", url, err)
+	}
+
+	return data, nil
+}
+
+// Convert converts a picture with the pipeline, reusing the result of a previous run if there is one.
+func (c *ImageCache) Convert(data []byte, pipeline ImagePipeline) ([]byte, error) {
+	path := c.convertedPath(hashBytes(data), pipeline)
+	if converted, err := c.read(path); err == nil {
+		return converted, nil
+	}
+
+	// Remember pictures that can't be converted too, so they aren't decoded again every hour. They are tried again
+	// after a while, as the failure may have been fixed since.
+	if info, err := os.Stat(path + ".err"); err == nil && time.Since(info.ModTime()) < conversionErrorTTL {
+		if reason, err := os.ReadFile(path + ".err"); err == nil {
+			return nil, errors.New(string(reason))
+		}
+	}
+
+	converted, err := pipeline.Process(data)
+	if err != nil {
+		if writeErr := c.write(path+".err", []byte(err.Error())); writeErr != nil {
+			log.Printf("Failed to cache image conversion: %v", writeErr)
+		}
+		return nil, err
+	}
+
+	if err = c.write(path, converted); err != nil {
+		log.Printf("Failed to cache image conversion: %v", err)
+	}
+
+	return converted, nil
+}
+
+// read reads a cached file and marks it as used.
+func (c *ImageCache) read(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return data, nil
+}
+
+// write writes a cached file through a temporary file, so that an interrupted run never leaves half a picture.
+func (c *ImageCache) write(path string, data []byte) error {
+	temporary := path + ".tmp"
+	err := os.WriteFile(temporary, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(temporary, path)
+}
+
+// Prune removes the least recently used files until the cache fits its maximum size.
+func (c *ImageCache) Prune() error {
+	type cachedFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	var files []cachedFile
+	var total int64
+	err := filepath.WalkDir(c.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		files = append(files, cachedFile{path, info.Size(), info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(files, func(a cachedFile, b cachedFile) int {
+		return a.modTime.Compare(b.modTime)
+	})
+
+	removed := 0
+	for _, file := range files {
+		if total <= c.maxBytes {
+			break
+		}
+
+		err = os.Remove(file.path)
+		if err != nil {
+			return err
+		}
+		total -= file.size
+		removed++
+	}
+
+	if removed != 0 {
+		log.Printf("Removed %d files from the image cache", removed)
+	}
+
+	return nil
+}
+
+// version identifies the pipeline settings and code, so conversions are redone when either changes.
+func (p ImagePipeline) version() string {
+	// The debug directory doesn't change the result.
+	p.DebugDir = ""
+	sum := sha256.Sum256(fmt.Appendf(nil, "%d %+v", imagePipelineVersion, p))
+	return hex.EncodeToString(sum[:8])
+}
+
+// fetchImage downloads a picture through the cache if there is one.
+func fetchImage(url string, userAgent ...string) ([]byte, error) {
+	if PictureCache != nil {
+		return PictureCache.Fetch(url, userAgent...)
+	}
+
+	return HttpGet(url, userAgent...)
+}
+
+// convertImage converts a picture through the cache if there is one.
+func convertImage(data []byte) ([]byte, error) {
+	if PictureCache != nil {
+		return PictureCache.Convert(data, Images)
+	}
+
+	return Images.Process(data)
+}
@@ -0,0 +1,156 @@
+package news
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestImageCacheRevalidates(t *testing.T) {
+	defer func(delay time.Duration) { retryDelay = delay }(retryDelay)
+	retryDelay = 0
+
+	picture := encodePNG(t, testPicture(400, 300))
+	downloads, failures := 0, 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first request fails, which is retried.
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		downloads++
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write(picture)
+	}))
+
+	cache, err := NewImageCache(t.TempDir(), 1024*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		data, err := cache.Fetch(server.URL + "/picture.png")
+		if err != nil || !bytes.Equal(data, picture) {
+			t.Fatalf("fetch %d: expected the picture, got %d bytes: %v", i, len(data), err)
+		}
+	}
+
+	if downloads != 1 {
+		t.Errorf("expected a single download, got %d", downloads)
+	}
+
+	// The cached copy is used when the source is down.
+	server.Close()
+	data, err := cache.Fetch(server.URL + "/picture.png")
+	if err != nil || !bytes.Equal(data, picture) {
+		t.Errorf("expected the cached picture, got %d bytes: %v", len(data), err)
+	}
+}
+
+func TestImageCacheConvert(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewImageCache(dir, 1024*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	picture := encodePNG(t, testPicture(400, 300))
+	first, err := cache.Convert(picture, DefaultImagePipeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := cache.Convert(picture, DefaultImagePipeline)
+	if err != nil || !bytes.Equal(first, second) {
+		t.Fatalf("expected the cached conversion, got %v", err)
+	}
+
+	// Changing the pipeline converts again.
+	pipeline := DefaultImagePipeline
+	pipeline.MaxWidth, pipeline.MaxHeight = 100, 75
+	_, err = cache.Convert(picture, pipeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	converted, _ := filepath.Glob(filepath.Join(dir, "converted", "*.jpg"))
+	if len(converted) != 2 {
+		t.Errorf("expected 2 conversions, got %d", len(converted))
+	}
+
+	// Failures are remembered too, for a while.
+	for i := 0; i < 2; i++ {
+		_, err = cache.Convert([]byte("not a picture"), DefaultImagePipeline)
+		if err == nil {
+			t.Errorf("conversion %d: expected an error", i)
+		}
+	}
+
+	failures, _ := filepath.Glob(filepath.Join(dir, "converted", "*.err"))
+	if len(failures) != 1 {
+		t.Fatalf("expected a remembered failure, got %v", failures)
+	}
+
+	// A remembered failure is ignored once it expires, and the picture is converted again.
+	err = os.WriteFile(failures[0], []byte("stale failure"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired := time.Now().Add(-conversionErrorTTL - time.Minute)
+	err = os.Chtimes(failures[0], expired, expired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = cache.Convert([]byte("not a picture"), DefaultImagePipeline)
+	if err == nil || err.Error() == "stale failure" {
+		t.Errorf("expected the picture to be converted again, got %v", err)
+	}
+}
+
+func TestImageCachePrune(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewImageCache(dir, 2500)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Three files of a kilobyte, used from oldest to newest.
+	names := []string{"old", "middle", "new"}
+	for i, name := range names {
+		path := filepath.Join(dir, "originals", name)
+		err = os.WriteFile(path, make([]byte, 1024), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		used := time.Now().Add(time.Duration(i-len(names)) * time.Hour)
+		err = os.Chtimes(path, used, used)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = cache.Prune()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range names {
+		_, err = os.Stat(filepath.Join(dir, "originals", name))
+		if exists := err == nil; exists != (name != "old") {
+			t.Errorf("%s: expected it to exist to be %v", name, name != "old")
+		}
+	}
+}
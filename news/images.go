@@ -0,0 +1,187 @@
+package news
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	// ErrImageTooSmall is returned for images too small to be worth showing, such as tracking pixels and icons.
+	ErrImageTooSmall = errors.New("image is too small")
+	// ErrImageOverBudget is returned when an image doesn't fit the byte budget even at the lowest quality.
+	ErrImageOverBudget = errors.New("image does not fit the byte budget")
+	// ErrUnsupportedImage is returned for images in a known format we can't decode, such as AVIF.
+	ErrUnsupportedImage = errors.New("unsupported image format")
+)
+
+// ImagePipeline describes how article pictures are converted for the channel.
+type ImagePipeline struct {
+	// AspectWidth and AspectHeight are the aspect ratio pictures are cropped to. Zero keeps the original ratio.
+	AspectWidth  int `xml:"AspectWidth"`
+	AspectHeight int `xml:"AspectHeight"`
+	// MaxWidth and MaxHeight are the largest dimensions of a converted picture. Pictures are never enlarged.
+	MaxWidth  int `xml:"MaxWidth"`
+	MaxHeight int `xml:"MaxHeight"`
+	// MinWidth and MinHeight are the smallest source dimensions accepted.
+	MinWidth  int `xml:"MinWidth"`
+	MinHeight int `xml:"MinHeight"`
+	// MaxBytes is the largest size of a converted picture.
+	MaxBytes int `xml:"MaxBytes"`
+	// Quality is the JPEG quality tried first. It is lowered by QualityStep until the picture fits MaxBytes,
+	// but never below MinQuality.
+	Quality     int `xml:"Quality"`
+	MinQuality  int `xml:"MinQuality"`
+	QualityStep int `xml:"QualityStep"`
+	// SmartCrop picks the crop that keeps the subject of the picture rather than its centre.
+	SmartCrop bool `xml:"SmartCrop"`
+	// DebugDir, if set, receives a picture of every smart crop with the rectangle drawn over it.
+	DebugDir string `xml:"DebugDir"`
+}
+
+// DefaultImagePipeline converts pictures to 4:3 JPEGs that fit the channel's picture area.
+var DefaultImagePipeline = ImagePipeline{
+	AspectWidth:  4,
+	AspectHeight: 3,
+	MaxWidth:     200,
+	MaxHeight:    150,
+	MinWidth:     64,
+	MinHeight:    48,
+	MaxBytes:     16 * 1024,
+	Quality:      85,
+	MinQuality:   30,
+	QualityStep:  10,
+	SmartCrop:    true,
+}
+
+// Images is the pipeline pictures are converted with. It is set by the generator from its configuration.
+var Images = DefaultImagePipeline
+
+// Validate makes sure the pipeline settings can produce a picture.
+func (p ImagePipeline) Validate() error {
+	switch {
+	case p.AspectWidth < 0 || p.AspectHeight < 0 || (p.AspectWidth == 0) != (p.AspectHeight == 0):
+		return fmt.Errorf("invalid aspect ratio %d:%d", p.AspectWidth, p.AspectHeight)
+	case p.MaxWidth <= 0 || p.MaxHeight <= 0:
+		return fmt.Errorf("invalid maximum dimensions %dx%d", p.MaxWidth, p.MaxHeight)
+	case p.MaxBytes <= 0:
+		return fmt.Errorf("invalid byte budget %d", p.MaxBytes)
+	case p.MinQuality < 1 || p.Quality < p.MinQuality || p.Quality > 100:
+		return fmt.Errorf("invalid quality range %d to %d", p.MinQuality, p.Quality)
+	case p.QualityStep <= 0:
+		return fmt.Errorf("invalid quality step %d", p.QualityStep)
+	}
+
+	return nil
+}
+
+// Process decodes a picture, crops and scales it, then encodes it as a baseline JPEG within the byte budget.
+// Re-encoding also drops any metadata the source had, such as EXIF or colour profiles.
+func (p ImagePipeline) Process(data []byte) ([]byte, error) {
+	// Animated GIFs decode to their first frame.
+	source, format, err := image.Decode(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		if format = unsupportedFormat(data); format != "" {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedImage, format)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := source.Bounds()
+	if bounds.Dx() < p.MinWidth || bounds.Dy() < p.MinHeight {
+		return nil, fmt.Errorf("%w: %s of %dx%d", ErrImageTooSmall, format, bounds.Dx(), bounds.Dy())
+	}
+
+	crop := p.cropRectangle(bounds)
+	if p.SmartCrop && p.AspectWidth != 0 {
+		crop = p.smartCrop(source)
+	}
+	width, height := p.scaledSize(crop.Dx(), crop.Dy())
+
+	// JPEG has no transparency, so transparent pictures are drawn over white rather than black.
+	resized := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(resized, resized.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.BiLinear.Scale(resized, resized.Bounds(), source, crop, draw.Over, nil)
+
+	// Go's encoder only writes baseline JPEGs, which is all the Wii can decode.
+	var encoded []byte
+	for quality := p.Quality; quality >= p.MinQuality; quality -= p.QualityStep {
+		var buffer bytes.Buffer
+		err = jpeg.Encode(&buffer, resized, &jpeg.Options{Quality: quality})
+		if err != nil {
+			return nil, err
+		}
+
+		encoded = buffer.Bytes()
+		if len(encoded) <= p.MaxBytes {
+			return encoded, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %d bytes for a budget of %d", ErrImageOverBudget, len(encoded), p.MaxBytes)
+}
+
+// unsupportedFormat names the format of images we know about but can't decode, or returns an empty string.
+func unsupportedFormat(data []byte) string {
+	// AVIF and HEIF are ISO media files whose first box declares the brand.
+	if len(data) >= 12 && string(data[4:8]) == "ftyp" {
+		switch string(data[8:12]) {
+		case "avif", "avis":
+			return "avif"
+		case "heic", "heix", "hevc", "mif1", "msf1":
+			return "heif"
+		}
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte{0xff, 0x0a}), bytes.HasPrefix(data, []byte("\x00\x00\x00\x0cJXL \r\n\x87\n")):
+		return "jxl"
+	case bytes.HasPrefix(data, []byte("BM")):
+		return "bmp"
+	case bytes.HasPrefix(data, []byte("II*\x00")), bytes.HasPrefix(data, []byte("MM\x00*")):
+		return "tiff"
+	}
+
+	head := strings.ToLower(string(data[:min(len(data), 512)]))
+	if strings.Contains(head, "<svg") {
+		return "svg"
+	}
+
+	return ""
+}
+
+// cropRectangle returns the centred part of bounds with the pipeline's aspect ratio.
+func (p ImagePipeline) cropRectangle(bounds image.Rectangle) image.Rectangle {
+	if p.AspectWidth == 0 {
+		return bounds
+	}
+
+	width, height := bounds.Dx(), bounds.Dy()
+	if width*p.AspectHeight > height*p.AspectWidth {
+		width = max(height*p.AspectWidth/p.AspectHeight, 1)
+	} else {
+		height = max(width*p.AspectHeight/p.AspectWidth, 1)
+	}
+
+	x := bounds.Min.X + (bounds.Dx()-width)/2
+	y := bounds.Min.Y + (bounds.Dy()-height)/2
+	return image.Rect(x, y, x+width, y+height)
+}
+
+// scaledSize fits the dimensions within the maximum dimensions, keeping their ratio.
+func (p ImagePipeline) scaledSize(width int, height int) (int, int) {
+	scale := min(float64(p.MaxWidth)/float64(width), float64(p.MaxHeight)/float64(height), 1)
+
+	return max(int(float64(width)*scale+0.5), 1), max(int(float64(height)*scale+0.5), 1)
+}
@@ -0,0 +1,263 @@
+package news
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// testPicture returns a picture with a gradient, so that it compresses like a photo rather than a flat colour.
+func testPicture(width int, height int) *image.RGBA {
+	picture := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			picture.Set(x, y, color.RGBA{R: uint8(x * 255 / width), G: uint8(y * 255 / height), B: 128, A: 255})
+		}
+	}
+
+	return picture
+}
+
+// noisyPicture returns a picture of random pixels, which JPEG can't compress well.
+func noisyPicture(width int, height int) *image.RGBA {
+	random := rand.New(rand.NewSource(1))
+	picture := image.NewRGBA(image.Rect(0, 0, width, height))
+	random.Read(picture.Pix)
+	for i := 3; i < len(picture.Pix); i += 4 {
+		picture.Pix[i] = 255
+	}
+
+	return picture
+}
+
+func encodePNG(t *testing.T, picture image.Image) []byte {
+	var buffer bytes.Buffer
+	err := png.Encode(&buffer, picture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func encodeJPEG(t *testing.T, picture image.Image) []byte {
+	var buffer bytes.Buffer
+	err := jpeg.Encode(&buffer, picture, &jpeg.Options{Quality: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+// solidWebP returns a lossless WebP of a single colour. With a single symbol in every prefix code, pixels take no
+// bits at all, so the whole bitstream is the header and the code definitions.
+func solidWebP(width int, height int, c color.NRGBA) []byte {
+	// Bits are packed starting from the least significant bit of each byte, after the signature byte.
+	bitstream := []byte{0x2f}
+	var count uint
+	write := func(value uint64, n uint) {
+		for i := uint(0); i < n; i, count = i+1, count+1 {
+			if count%8 == 0 {
+				bitstream = append(bitstream, 0)
+			}
+			bitstream[len(bitstream)-1] |= byte(value>>i&1) << (count % 8)
+		}
+	}
+
+	write(uint64(width-1), 14)
+	write(uint64(height-1), 14)
+	write(0, 1) // No alpha
+	write(0, 3) // Version
+	write(0, 1) // No transform
+	write(0, 1) // No colour cache
+	write(0, 1) // No meta prefix codes
+	for _, symbol := range []uint8{c.G, c.R, c.B, c.A} {
+		write(1, 1) // Simple code
+		write(0, 1) // One symbol
+		write(1, 1) // Eight bit symbol
+		write(uint64(symbol), 8)
+	}
+	write(1, 1) // Distance code, which is never used
+	write(0, 1)
+	write(0, 1)
+	write(0, 1)
+
+	if len(bitstream)%2 != 0 {
+		bitstream = append(bitstream, 0)
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString("RIFF")
+	_ = binary.Write(&buffer, binary.LittleEndian, uint32(4+8+len(bitstream)))
+	buffer.WriteString("WEBPVP8L")
+	_ = binary.Write(&buffer, binary.LittleEndian, uint32(len(bitstream)))
+	buffer.Write(bitstream)
+	return buffer.Bytes()
+}
+
+func TestImagePipelineFormats(t *testing.T) {
+	inputs := map[string][]byte{
+		"png":  encodePNG(t, testPicture(800, 450)),
+		"jpeg": encodeJPEG(t, testPicture(600, 800)),
+	}
+
+	for name, data := range inputs {
+		converted, err := DefaultImagePipeline.Process(data)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+
+		if len(converted) > DefaultImagePipeline.MaxBytes {
+			t.Errorf("%s: %d bytes is over the budget", name, len(converted))
+		}
+
+		picture, format, err := image.Decode(bytes.NewReader(converted))
+		if err != nil || format != "jpeg" {
+			t.Errorf("%s: expected a JPEG, got %q: %v", name, format, err)
+			continue
+		}
+
+		if size := picture.Bounds().Size(); size != image.Pt(200, 150) {
+			t.Errorf("%s: expected 200x150, got %v", name, size)
+		}
+	}
+}
+
+func TestImagePipelineWebPAndGIF(t *testing.T) {
+	var gifBuffer bytes.Buffer
+	err := gif.Encode(&gifBuffer, testPicture(320, 240), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := map[string][]byte{
+		"webp": solidWebP(320, 240, color.NRGBA{R: 200, G: 50, B: 50, A: 255}),
+		"gif":  gifBuffer.Bytes(),
+	}
+
+	for name, data := range inputs {
+		converted, err := DefaultImagePipeline.Process(data)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+
+		config, err := jpeg.DecodeConfig(bytes.NewReader(converted))
+		if err != nil || config.Width != 200 || config.Height != 150 {
+			t.Errorf("%s: expected a 200x150 JPEG, got %+v: %v", name, config, err)
+		}
+	}
+}
+
+func TestImagePipelineUnsupported(t *testing.T) {
+	inputs := map[string][]byte{
+		"avif": []byte("\x00\x00\x00\x1cftypavif\x00\x00\x00\x00avifmif1miaf"),
+		"heif": []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00mif1heic"),
+		"svg":  []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`),
+	}
+
+	for name, data := range inputs {
+		_, err := DefaultImagePipeline.Process(data)
+		if !errors.Is(err, ErrUnsupportedImage) || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: expected an unsupported format error, got %v", name, err)
+		}
+	}
+}
+
+func TestImagePipelineStripsMetadata(t *testing.T) {
+	// Insert an EXIF segment after the start of image marker.
+	data := encodeJPEG(t, testPicture(400, 300))
+	exif := append([]byte{0xff, 0xe1, 0x00, 0x10}, "Exif\x00\x00testdata"...)
+	data = append(append(append([]byte{}, data[:2]...), exif...), data[2:]...)
+
+	converted, err := DefaultImagePipeline.Process(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(converted, []byte("Exif")) {
+		t.Error("expected the EXIF segment to be removed")
+	}
+
+	// Baseline JPEGs use the SOF0 marker, progressive ones SOF2.
+	if !bytes.Contains(converted, []byte{0xff, 0xc0}) || bytes.Contains(converted, []byte{0xff, 0xc2}) {
+		t.Error("expected a baseline JPEG")
+	}
+}
+
+func TestImagePipelineNeverEnlarges(t *testing.T) {
+	converted, err := DefaultImagePipeline.Process(encodePNG(t, testPicture(120, 90)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := jpeg.DecodeConfig(bytes.NewReader(converted))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Width != 120 || config.Height != 90 {
+		t.Errorf("expected 120x90, got %dx%d", config.Width, config.Height)
+	}
+}
+
+func TestImagePipelineByteBudget(t *testing.T) {
+	pipeline := DefaultImagePipeline
+	data := encodePNG(t, noisyPicture(400, 300))
+
+	full, err := pipeline.Process(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Lowering the budget has to lower the quality to fit.
+	pipeline.MaxBytes = len(full) - 1
+	reduced, err := pipeline.Process(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reduced) >= len(full) {
+		t.Errorf("expected fewer than %d bytes, got %d", len(full), len(reduced))
+	}
+
+	pipeline.MaxBytes = 100
+	_, err = pipeline.Process(data)
+	if !errors.Is(err, ErrImageOverBudget) {
+		t.Errorf("expected an over budget error, got %v", err)
+	}
+}
+
+func TestImagePipelineRejects(t *testing.T) {
+	_, err := DefaultImagePipeline.Process(encodePNG(t, testPicture(1, 1)))
+	if !errors.Is(err, ErrImageTooSmall) {
+		t.Errorf("expected a too small error, got %v", err)
+	}
+
+	data := encodeJPEG(t, testPicture(400, 300))
+	_, err = DefaultImagePipeline.Process(data[:len(data)/2])
+	if err == nil {
+		t.Error("expected a truncated JPEG to be rejected")
+	}
+}
+
+func TestImagePipelineValidate(t *testing.T) {
+	if err := DefaultImagePipeline.Validate(); err != nil {
+		t.Errorf("expected the default pipeline to be valid, got %v", err)
+	}
+
+	pipeline := DefaultImagePipeline
+	pipeline.AspectHeight = 0
+	if pipeline.Validate() == nil {
+		t.Error("expected a missing aspect height to be invalid")
+	}
+}
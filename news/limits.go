@@ -0,0 +1,205 @@
+package news
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Names of the texts of an article that have a limit.
+const (
+	FieldHeadline       = "headline"
+	FieldBannerHeadline = "banner_headline"
+	FieldBody           = "body"
+	FieldCaption        = "caption"
+	FieldLocationName   = "location_name"
+)
+
+// TextLimits are the most UTF-16 units each text of an article may take, not counting the null terminator.
+// A limit of zero leaves the text as it is.
+type TextLimits struct {
+	Headline int `xml:"Headline"`
+	// BannerHeadline is the limit of the headlines scrolling on the News Channel banner in the Wii Menu.
+	BannerHeadline int `xml:"BannerHeadline"`
+	Body           int `xml:"Body"`
+	Caption        int `xml:"Caption"`
+	LocationName   int `xml:"LocationName"`
+}
+
+var DefaultTextLimits = TextLimits{
+	Headline:       100,
+	BannerHeadline: 60,
+	Body:           3000,
+	Caption:        150,
+	LocationName:   40,
+}
+
+// Limits are the limits articles are truncated to.
+var Limits = DefaultTextLimits
+
+// Validate checks that no limit is negative, and that there is room for more than an ellipsis.
+func (l TextLimits) Validate() error {
+	for _, limit := range []int{l.Headline, l.BannerHeadline, l.Body, l.Caption, l.LocationName} {
+		if limit < 0 {
+			return errors.New("limits can't be negative")
+		}
+		if limit != 0 && limit < 10 {
+			return errors.New("limits must be at least 10 units")
+		}
+	}
+
+	return nil
+}
+
+// ellipsis returns the ellipsis marking truncated text in a language. Japanese doubles it.
+func ellipsis(language string) string {
+	if language == "ja" {
+		return "……"
+	}
+
+	return "…"
+}
+
+// utf16Length returns the number of UTF-16 units of r.
+func utf16Length(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+
+	return 1
+}
+
+// prefixLength returns the length in bytes of the longest prefix of text that fits in limit UTF-16 units.
+func prefixLength(text string, limit int) (int, bool) {
+	units := 0
+	for i, r := range text {
+		units += utf16Length(r)
+		if units > limit {
+			return i, false
+		}
+	}
+
+	return len(text), true
+}
+
+// isSentenceEnd reports whether a sentence ends with r.
+func isSentenceEnd(r rune) bool {
+	return strings.ContainsRune(".!?。！？", r)
+}
+
+// isClosing reports whether r closes a quote or parenthesis, which stays with the sentence it ends.
+func isClosing(r rune) bool {
+	return strings.ContainsRune("\"'»”’)」』）", r)
+}
+
+// sentenceEnd returns the length of the longest run of whole sentences at the start of text, or zero if there are
+// none. Sentences end with their punctuation and any closing quote, followed by a space, a line break, or, in
+// languages written without spaces, anything.
+func sentenceEnd(text string) int {
+	end := 0
+	for i, r := range text {
+		if !isSentenceEnd(r) {
+			continue
+		}
+
+		j := i + utf8.RuneLen(r)
+		for j < len(text) {
+			next, size := utf8.DecodeRuneInString(text[j:])
+			if !isClosing(next) && !isSentenceEnd(next) {
+				break
+			}
+			j += size
+		}
+
+		next, _ := utf8.DecodeRuneInString(text[j:])
+		if j == len(text) || unicode.IsSpace(next) || r >= 0x3000 {
+			end = j
+		}
+	}
+
+	return end
+}
+
+// Truncate shortens text to at most limit UTF-16 units, ellipsis included. It cuts at the last word boundary
+// unless that would lose more than half of the text, as happens in languages written without spaces.
+// It returns whether text was truncated.
+func Truncate(text string, limit int, language string) (string, bool) {
+	if limit <= 0 {
+		return text, false
+	}
+	if _, fits := prefixLength(text, limit); fits {
+		return text, false
+	}
+
+	mark := ellipsis(language)
+	cut, _ := prefixLength(text, limit-len([]rune(mark)))
+	prefix := text[:cut]
+
+	if space := strings.LastIndexFunc(prefix, unicode.IsSpace); space > len(prefix)/2 {
+		prefix = prefix[:space]
+	}
+
+	// Don't leave a dangling comma or dash before the ellipsis.
+	prefix = strings.TrimRightFunc(prefix, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(",;:-–—、", r)
+	})
+
+	return prefix + mark, true
+}
+
+// TruncateSentences shortens text to at most limit UTF-16 units, keeping whole sentences when at least half of
+// the text can be kept that way. Otherwise it is cut like Truncate. It returns whether text was truncated.
+func TruncateSentences(text string, limit int, language string) (string, bool) {
+	if limit <= 0 {
+		return text, false
+	}
+
+	cut, fits := prefixLength(text, limit)
+	if fits {
+		return text, false
+	}
+
+	if end := sentenceEnd(text[:cut]); end > cut/2 {
+		return strings.TrimSpace(text[:end]), true
+	}
+
+	return Truncate(text, limit, language)
+}
+
+// ApplyLimits truncates the texts of an article to Limits and returns the fields that were truncated.
+// The banner headline is limited when the banner is made, as only some headlines are shown there.
+func ApplyLimits(article *Article, language string) []string {
+	var truncated []string
+	limit := func(field string, text string, limit int, truncate func(string, int, string) (string, bool)) string {
+		text, wasTruncated := truncate(text, limit, language)
+		if wasTruncated {
+			truncated = append(truncated, field)
+		}
+		return text
+	}
+
+	article.Title = limit(FieldHeadline, article.Title, Limits.Headline, Truncate)
+	if article.Content != nil {
+		content := limit(FieldBody, *article.Content, Limits.Body, TruncateSentences)
+		article.Content = &content
+	}
+
+	// Locations and pictures can be shared with other articles, so they are copied rather than changed.
+	if article.Location != nil {
+		location := *article.Location
+		location.Name = limit(FieldLocationName, location.Name, Limits.LocationName, Truncate)
+		article.Location = &location
+	}
+
+	if article.Pictures != nil {
+		pictures := make([]Thumbnail, len(article.Pictures))
+		for i, picture := range article.Pictures {
+			picture.Caption = limit(FieldCaption, picture.Caption, Limits.Caption, TruncateSentences)
+			pictures[i] = picture
+		}
+		article.Pictures = pictures
+	}
+
+	return truncated
+}
@@ -0,0 +1,103 @@
+package news
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text     string
+		limit    int
+		language string
+		expected string
+	}{
+		{"Short enough", 20, "en", "Short enough"},
+		{"No limit at all", 0, "en", "No limit at all"},
+		{"Council approves the new harbour bridge after years of debate", 30, "en", "Council approves the new…"},
+		{"Le conseil approuve le pont, après des années", 30, "fr", "Le conseil approuve le pont…"},
+		{"東京都で新しい橋の建設が承認されました", 12, "ja", "東京都で新しい橋の建……"},
+		// Characters outside of the basic plane take two units and are never split.
+		{"Tokyo 𠮷野家 opens a new branch", 9, "en", "Tokyo 𠮷…"},
+	}
+
+	for _, test := range tests {
+		actual, truncated := Truncate(test.text, test.limit, test.language)
+		if actual != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.text, test.expected, actual)
+		}
+		if truncated != (actual != test.text) {
+			t.Errorf("%q: expected truncated to be %v", test.text, actual != test.text)
+		}
+		if test.limit != 0 && len(utf16.Encode([]rune(actual))) > test.limit {
+			t.Errorf("%q: %q is over the limit of %d", test.text, actual, test.limit)
+		}
+	}
+}
+
+func TestTruncateSentences(t *testing.T) {
+	tests := []struct {
+		text     string
+		limit    int
+		language string
+		expected string
+	}{
+		{"The first sentence. The second one. The third one.", 40, "en", "The first sentence. The second one."},
+		{"He said: \"It is over.\" Then he left the room.", 30, "en", "He said: \"It is over.\""},
+		{"It costs 3.5 million euros to build and maintain the bridge.", 30, "en", "It costs 3.5 million euros…"},
+		{"橋が完成した。開通は来月の予定だ。", 12, "ja", "橋が完成した。"},
+		// A first sentence that is too short gives way to cutting at a word.
+		{"Yes. The council approves the new harbour bridge after years", 40, "en", "Yes. The council approves the new…"},
+	}
+
+	for _, test := range tests {
+		actual, _ := TruncateSentences(test.text, test.limit, test.language)
+		if actual != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestApplyLimits(t *testing.T) {
+	defer func(limits TextLimits) {
+		Limits = limits
+	}(Limits)
+	Limits = TextLimits{Headline: 20, Body: 20, Caption: 15, LocationName: 40}
+
+	content := "Short body."
+	location := &Location{Name: "Tokyo"}
+	pictures := []Thumbnail{{Caption: "A very long caption for a picture"}}
+	article := Article{
+		Title:    "A headline that is much too long",
+		Content:  &content,
+		Location: location,
+		Pictures: pictures,
+	}
+
+	truncated := ApplyLimits(&article, "en")
+	if !reflect.DeepEqual(truncated, []string{FieldHeadline, FieldCaption}) {
+		t.Errorf("unexpected truncated fields %v", truncated)
+	}
+
+	if article.Title != "A headline that is…" || *article.Content != content || article.Pictures[0].Caption != "A very long…" {
+		t.Errorf("unexpected article %q %q %q", article.Title, *article.Content, article.Pictures[0].Caption)
+	}
+	if pictures[0].Caption != "A very long caption for a picture" {
+		t.Error("expected the original pictures to be unchanged")
+	}
+}
+
+func TestTextLimitsValidate(t *testing.T) {
+	if err := DefaultTextLimits.Validate(); err != nil {
+		t.Errorf("expected the default limits to be valid: %v", err)
+	}
+
+	if err := (TextLimits{Headline: -1}).Validate(); err == nil {
+		t.Error("expected a negative limit to be rejected")
+	}
+
+	if err := (TextLimits{Body: 3}).Validate(); err == nil {
+		t.Error("expected a limit too short for an ellipsis to be rejected")
+	}
+}
@@ -0,0 +1,177 @@
+package news
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// locationDataVersion is the newest version of the location data format we understand.
+const locationDataVersion = 1
+
+// LocationData is the contents of a single language's location data file.
+type LocationData struct {
+	Version   int                 `json:"version"`
+	Language  string              `json:"language"`
+	Blocked   []string            `json:"blocked"`
+	Locations []LocationDataEntry `json:"locations"`
+
+	// Where the file was read from, used for reporting.
+	path string
+}
+
+// LocationDataEntry is a pinned location. The key is what we match extracted location names against.
+type LocationDataEntry struct {
+	Key       string  `json:"key"`
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+//go:embed data/locations/*.json
+var locationDataFS embed.FS
+
+// BlockedLocations contains names that are extracted as locations but are not real places.
+var BlockedLocations = map[string]bool{}
+
+// CommonLocations contains locations that we know the coordinates of without asking Nominatim.
+var CommonLocations = map[string]Location{}
+
+func init() {
+	// The embedded data is validated by the tests, so failing here means the binary itself is broken.
+	err := LoadLocations("")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// LoadLocations fills CommonLocations and BlockedLocations from the embedded data files.
+// If dir is not empty, any <language>.json file inside it replaces the embedded file for that language.
+func LoadLocations(dir string) error {
+	files, err := ReadLocationData(dir)
+	if err != nil {
+		return err
+	}
+
+	blocked := make(map[string]bool)
+	common := make(map[string]Location)
+	for _, file := range files {
+		if file.Version > locationDataVersion {
+			return fmt.Errorf("%s: unsupported location data version %d", file.path, file.Version)
+		}
+
+		for _, key := range file.Blocked {
+			blocked[key] = true
+		}
+
+		for _, entry := range file.Locations {
+			common[entry.Key] = Location{
+				Longitude: entry.Longitude,
+				Latitude:  entry.Latitude,
+				Name:      entry.Name,
+			}
+		}
+	}
+
+	BlockedLocations = blocked
+	CommonLocations = common
+	return nil
+}
+
+// ReadLocationData reads every language's data file, preferring the ones found in dir over the embedded copies.
+func ReadLocationData(dir string) ([]LocationData, error) {
+	sources, err := readDataFiles(locationDataFS, "data/locations/*.json", dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []LocationData
+	for _, src := range sources {
+		file := LocationData{path: src.path}
+		err = json.Unmarshal(src.data, &file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src.path, err)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// ValidateLocationData checks the location data that LoadLocations would use and returns every problem found.
+func ValidateLocationData(dir string) []error {
+	files, err := ReadLocationData(dir)
+	if err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+	report := func(file LocationData, format string, args ...any) {
+		problems = append(problems, fmt.Errorf("%s: %s", file.path, fmt.Sprintf(format, args...)))
+	}
+
+	// Keys are merged across languages, so duplicates are checked across every file.
+	blockedIn := make(map[string]string)
+	locationIn := make(map[string]string)
+	for _, file := range files {
+		if file.Version < 1 || file.Version > locationDataVersion {
+			report(file, "unsupported version %d", file.Version)
+		}
+
+		language := strings.TrimSuffix(filepath.Base(file.path), ".json")
+		if file.Language != language {
+			report(file, "language %q does not match the file name", file.Language)
+		}
+
+		for _, key := range file.Blocked {
+			if key != strings.ToUpper(key) {
+				report(file, "blocked key %q is not upper case and will never match", key)
+			}
+			if previous, exists := blockedIn[key]; exists {
+				report(file, "blocked key %q is already blocked in %s", key, previous)
+			}
+			blockedIn[key] = file.path
+		}
+
+		for _, entry := range file.Locations {
+			if entry.Key == "" {
+				report(file, "location %q has no key", entry.Name)
+				continue
+			}
+			if entry.Key != strings.ToUpper(entry.Key) {
+				report(file, "location key %q is not upper case and will never match", entry.Key)
+			}
+			if previous, exists := locationIn[entry.Key]; exists {
+				report(file, "location key %q is already defined in %s", entry.Key, previous)
+			}
+			locationIn[entry.Key] = file.path
+
+			if entry.Name == "" {
+				report(file, "location %q has no name", entry.Key)
+			}
+			if entry.Latitude < -90 || entry.Latitude > 90 {
+				report(file, "location %q has out of range latitude %f", entry.Key, entry.Latitude)
+			}
+			if entry.Longitude < -180 || entry.Longitude > 180 {
+				report(file, "location %q has out of range longitude %f", entry.Key, entry.Longitude)
+			}
+			if entry.Latitude == 0 && entry.Longitude == 0 {
+				report(file, "location %q has no coordinates", entry.Key)
+			}
+		}
+	}
+
+	// Blocked entries win over common ones, meaning such a location can never be used.
+	for _, file := range files {
+		for _, entry := range file.Locations {
+			if previous, exists := blockedIn[entry.Key]; exists {
+				report(file, "location key %q is blocked in %s", entry.Key, previous)
+			}
+		}
+	}
+
+	return problems
+}
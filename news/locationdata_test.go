@@ -0,0 +1,52 @@
+package news
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEmbeddedLocationDataIsValid(t *testing.T) {
+	for _, problem := range ValidateLocationData("") {
+		t.Error(problem)
+	}
+}
+
+func TestLocationDataOverride(t *testing.T) {
+	dir := t.TempDir()
+	data := `{
+  "version": 1,
+  "language": "en",
+  "blocked": ["WORLD", "TOKYO"],
+  "locations": [
+    {"key": "TOKYO", "name": "Tokyo", "latitude": 35.68, "longitude": 139.69},
+    {"key": "TOKYO", "name": "Tokyo", "latitude": 35.68, "longitude": 139.69},
+    {"key": "Nowhere", "name": "Nowhere", "latitude": 95, "longitude": 10}
+  ]
+}`
+	err := os.WriteFile(filepath.Join(dir, "en.json"), []byte(data), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Duplicate key, lower case key, out of range latitude and two blocklist conflicts.
+	problems := ValidateLocationData(dir)
+	if len(problems) != 5 {
+		t.Errorf("expected 5 problems, got %d: %v", len(problems), problems)
+	}
+
+	t.Cleanup(func() {
+		_ = LoadLocations("")
+	})
+
+	err = LoadLocations(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := CommonLocations["AMSTERDAM"]; exists {
+		t.Error("embedded English locations should have been replaced by the override")
+	}
+	if !BlockedLocations["DEPORTES"] {
+		t.Error("embedded Spanish blocklist should still be loaded")
+	}
+}
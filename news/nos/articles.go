@@ -43,6 +43,15 @@ func (a *nos) GetArticles() ([]news.Article, error) {
 	}
 	articles = append(articles, temp...)
 
+	// Additional feeds from the feeds data files.
+	for _, feed := range news.FeedsFor("nos") {
+		temp, err = a.getArticles(feed.URL, feed.Topic)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, temp...)
+	}
+
 	return articles, nil
 }
 
@@ -59,7 +68,9 @@ func (a *nos) GetSportsArticles() ([]news.Article, error) {
 }
 
 func (a *nos) GetEntertainmentArticles() ([]news.Article, error) {
-	return a.getArticles("https://feeds.nos.nl/nosnieuwsopmerkelijk", news.Entertainment)
+	// Remarkable news is only loosely entertainment, so the classifier may file it elsewhere.
+	return a.getArticles("https://feeds.nos.nl/nosnieuwsopmerkelijk", news.Entertainment,
+		news.NationalNews, news.InternationalNews, news.Sports, news.Science, news.Technology)
 }
 
 func (a *nos) GetBusinessArticles() ([]news.Article, error) {
@@ -2,9 +2,11 @@ package nos
 
 import (
 	"NewsChannel/news"
+	"bytes"
 	"encoding/xml"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -35,10 +37,10 @@ type Item struct {
 }
 
 type nos struct {
-	oldArticleTitles []string
+	dedup *news.Deduplicator
 }
 
-func (f *nos) getArticles(url string, topic news.Topic) ([]news.Article, error) {
+func (f *nos) getArticles(url string, topic news.Topic, alternatives ...news.Topic) ([]news.Article, error) {
 	// Fetch RSS XML
 	data, err := news.HttpGet(url)
 	if err != nil {
@@ -59,42 +61,60 @@ func (f *nos) getArticles(url string, topic news.Topic) ([]news.Article, error)
 		}
 
 		title := news.SanitizeText(item.Title)
-		// Check for duplicates
-		if news.IsDuplicateArticle(f.oldArticleTitles, title) {
-			continue
-		}
-		f.oldArticleTitles = append(f.oldArticleTitles, title)
 
 		// Extract content from RSS
-		content := f.extractContentFromDescription(item.Description)
+		body := news.ParseBodyHTML(item.Description)
+		content := body.Render()
 
-		// Get location by scraping the article page for meta tags
-		location := f.getLocationFromArticlePage(item.Link)
-
-		// Get thumbnail from RSS
-		var thumbnail *news.Thumbnail
-		if item.Enclosure.URL != "" && strings.Contains(item.Enclosure.Type, "image") {
-			imageData, err := news.HttpGet(item.Enclosure.URL)
-			if err == nil && len(imageData) > 0 {
-				caption := f.extractImageCaption(item.Link)
-				thumbnail = &news.Thumbnail{
-					Image:   news.ConvertImage(imageData),
-					Caption: news.SanitizeText(caption),
-				}
-			}
+		// Check for duplicates
+		skip := news.SkipEvent{
+			Source: "nos",
+			Topic:  topic.String(),
+			URL:    item.Link,
+			Title:  title,
+		}
+		if match, duplicate := f.dedup.Find(title, news.Lead(content)); duplicate {
+			skip.Reason, skip.MatchedTitle, skip.Score = news.SkipDuplicate, match.Title, match.Score
+			news.ReportSkip(skip)
+			continue
 		}
 
 		// Skip if no content
 		if len(content) == 0 {
+			skip.Reason = news.SkipNoContent
+			news.ReportSkip(skip)
 			continue
 		}
+		f.dedup.Add(title, news.Lead(content))
+
+		// The article page has the location in its meta tags and the credit of the picture.
+		page := f.getArticlePage(item.Link)
+		location := f.extractLocationFromContent(page)
+
+		// Get picture from RSS
+		var pictures []news.Thumbnail
+		if item.Enclosure.URL != "" && strings.Contains(item.Enclosure.Type, "image") {
+			imageData := news.ConvertFirstImage([]string{item.Enclosure.URL})
+			if imageData != nil {
+				credit := f.extractImageCredit(page)
+				pictures = []news.Thumbnail{{
+					Image:  imageData,
+					Credit: news.SanitizeText(credit),
+				}}
+			}
+		}
 
 		article := news.Article{
-			Title:     title,
-			Content:   &content,
-			Topic:     topic,
-			Location:  location,
-			Thumbnail: thumbnail,
+			Title:             title,
+			Source:            skip.Source,
+			URL:               skip.URL,
+			Content:           &content,
+			Body:              body,
+			Topic:             topic,
+			AlternativeTopics: alternatives,
+			Location:          location,
+			Pictures:          pictures,
+			PublishedTime:     news.ParseTime(item.PubDate, time.RFC1123Z, time.RFC1123),
 		}
 
 		articles = append(articles, article)
@@ -103,43 +123,32 @@ func (f *nos) getArticles(url string, topic news.Topic) ([]news.Article, error)
 	return articles, nil
 }
 
-func (f *nos) extractContentFromDescription(description string) string {
-	var ret string
-
-	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(description))
-	text := strings.Split(doc.Text(), "\n")
-
-	for _, paragraph := range text {
-		sanitized := news.SanitizeText(paragraph)
-		ret += sanitized
-		ret += "\n\n"
-	}
-
-	return strings.TrimSpace(ret)
-}
-
-func (f *nos) getLocationFromArticlePage(articleURL string) *news.Location {
+// getArticlePage fetches and parses an article page. It returns nil if that fails.
+func (f *nos) getArticlePage(articleURL string) *goquery.Document {
 	if articleURL == "" {
 		return nil
 	}
 
 	data, err := news.HttpGet(articleURL)
 	if err != nil {
-		log.Printf("Failed to fetch article page for location: %v", err)
+		log.Printf("Failed to fetch article page: %v", err)
 		return nil
 	}
 
-	html := string(data)
-	return f.extractLocationFromContent(html)
-}
-
-func (f *nos) extractLocationFromContent(html string) *news.Location {
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
 	if err != nil {
 		log.Println("Failed to parse HTML:", err)
 		return nil
 	}
 
+	return doc
+}
+
+func (f *nos) extractLocationFromContent(doc *goquery.Document) *news.Location {
+	if doc == nil {
+		return nil
+	}
+
 	// Try to find location in meta keywords
 	var candidates []string
 	doc.Find(`meta[name="keywords"]`).EachWithBreak(func(i int, s *goquery.Selection) bool {
@@ -158,18 +167,8 @@ func (f *nos) extractLocationFromContent(html string) *news.Location {
 	return news.GetLocationForExtractedLocation(candidates, "nl")
 }
 
-func (f *nos) extractImageCaption(articleURL string) string {
-	if articleURL == "" {
-		return ""
-	}
-
-	data, err := news.HttpGet(articleURL)
-	if err != nil {
-		return ""
-	}
-
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(data)))
-	if err != nil {
+func (f *nos) extractImageCredit(doc *goquery.Document) string {
+	if doc == nil {
 		return ""
 	}
 
@@ -1,6 +1,7 @@
 package nos
 
 import (
+	"NewsChannel/news"
 	_ "embed"
 	"fmt"
 	"strconv"
@@ -11,9 +12,9 @@ import (
 //go:embed logo.jpg
 var Logo []byte
 
-func NewNos(oldArticleTitles []string) *nos {
+func NewNos(dedup *news.Deduplicator) *nos {
 	return &nos{
-		oldArticleTitles: oldArticleTitles,
+		dedup: dedup,
 	}
 }
 
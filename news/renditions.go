@@ -0,0 +1,225 @@
+package news
+
+import (
+	"log"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// shareImageSelectors find the pictures a page offers for link previews, which are the best pick for a thumbnail.
+var shareImageSelectors = []struct {
+	selector  string
+	attribute string
+}{
+	{`meta[property="og:image"]`, "content"},
+	{`meta[property="og:image:secure_url"]`, "content"},
+	{`meta[property="og:image:url"]`, "content"},
+	{`meta[name="twitter:image"]`, "content"},
+	{`meta[name="twitter:image:src"]`, "content"},
+	{`link[rel="image_src"]`, "href"},
+}
+
+// PictureFallbacks is the most gallery pictures kept after an article's lead picture. The console shows a single
+// picture per article, so they are only used if the lead picture can't be converted or doesn't fit the image budget.
+var PictureFallbacks = 2
+
+// ImageCandidates returns the URLs of the pictures of an article page, best first, resolved against the page URL.
+// preferred URLs, such as the one given by the feed, come first. The rest are the pictures the page offers for
+// link previews, then the renditions of the pictures in its figures.
+func ImageCandidates(doc *goquery.Document, pageURL string, preferred ...string) []string {
+	candidates := newCandidateList(pageURL)
+	for _, candidate := range preferred {
+		candidates.add(candidate)
+	}
+
+	for _, share := range shareImageSelectors {
+		doc.Find(share.selector).Each(func(i int, s *goquery.Selection) {
+			candidates.add(s.AttrOr(share.attribute, ""))
+		})
+	}
+
+	doc.Find("figure").Each(func(i int, figure *goquery.Selection) {
+		candidates.addFigure(figure)
+	})
+
+	return candidates.urls
+}
+
+// GalleryFallbacks returns the pictures of the figures of an article page other than the lead picture, which was
+// converted from leadURL, at most count of them, to fall back on. They are lazy, so nothing is downloaded until they
+// are needed.
+func GalleryFallbacks(doc *goquery.Document, pageURL string, leadURL string, count int, userAgent ...string) []Thumbnail {
+	var pictures []Thumbnail
+	doc.Find("figure").EachWithBreak(func(i int, figure *goquery.Selection) bool {
+		if len(pictures) >= count {
+			return false
+		}
+
+		candidates := newCandidateList(pageURL)
+		candidates.addFigure(figure)
+		if len(candidates.urls) == 0 || slices.ContainsFunc(candidates.urls, func(candidate string) bool {
+			return sameImage(candidate, leadURL)
+		}) {
+			return true
+		}
+
+		caption, credit := FigureCaptionCredit(figure)
+		pictures = append(pictures, LazyThumbnail(candidates.urls, caption, credit, userAgent...))
+		return true
+	})
+
+	return pictures
+}
+
+// sameImage reports whether two URLs are renditions of the same picture. Image servers commonly choose the size and
+// format from the query, so it is left out.
+func sameImage(a string, b string) bool {
+	first, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	second, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+
+	return first.Host == second.Host && first.Path == second.Path
+}
+
+// candidateList collects picture URLs resolved against a page, without duplicates.
+type candidateList struct {
+	base *url.URL
+	urls []string
+}
+
+func newCandidateList(pageURL string) *candidateList {
+	base, _ := url.Parse(pageURL)
+	return &candidateList{base: base}
+}
+
+func (c *candidateList) add(candidate string) {
+	candidate = strings.TrimSpace(candidate)
+	if candidate == "" || strings.HasPrefix(candidate, "data:") {
+		return
+	}
+
+	if c.base != nil {
+		resolved, err := c.base.Parse(candidate)
+		if err != nil {
+			return
+		}
+		candidate = resolved.String()
+	} else if strings.HasPrefix(candidate, "//") {
+		candidate = "https:" + candidate
+	}
+
+	if !slices.Contains(c.urls, candidate) {
+		c.urls = append(c.urls, candidate)
+	}
+}
+
+// addFigure adds the renditions of the picture of a figure.
+func (c *candidateList) addFigure(figure *goquery.Selection) {
+	figure.Find("source[srcset], img[srcset]").Each(func(j int, s *goquery.Selection) {
+		for _, candidate := range parseSrcset(s.AttrOr("srcset", "")) {
+			c.add(candidate)
+		}
+	})
+	figure.Find("img").Each(func(j int, s *goquery.Selection) {
+		c.add(s.AttrOr("src", ""))
+		c.add(s.AttrOr("data-src", ""))
+	})
+}
+
+// parseSrcset returns the URLs of a srcset attribute, starting with the smallest rendition large enough for the
+// pipeline, so we don't download more than we need, followed by the smaller renditions from largest to smallest.
+func parseSrcset(srcset string) []string {
+	type rendition struct {
+		url   string
+		width int
+	}
+
+	var renditions []rendition
+	for _, entry := range strings.Split(srcset, ",") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// Renditions without a width descriptor, or with a density one, are treated as being large enough.
+		width := Images.MaxWidth
+		if len(fields) > 1 && strings.HasSuffix(fields[1], "w") {
+			parsed, err := strconv.Atoi(strings.TrimSuffix(fields[1], "w"))
+			if err == nil {
+				width = parsed
+			}
+		}
+
+		renditions = append(renditions, rendition{fields[0], width})
+	}
+
+	slices.SortStableFunc(renditions, func(a rendition, b rendition) int {
+		aLarge, bLarge := a.width >= Images.MaxWidth, b.width >= Images.MaxWidth
+		switch {
+		case aLarge && !bLarge:
+			return -1
+		case !aLarge && bLarge:
+			return 1
+		case aLarge:
+			return a.width - b.width
+		default:
+			return b.width - a.width
+		}
+	})
+
+	var urls []string
+	for _, r := range renditions {
+		urls = append(urls, r.url)
+	}
+
+	return urls
+}
+
+// ConvertFirstImage downloads the candidates in order and returns the first one that converts,
+// or nil if none do. Pictures go through PictureCache if it is set.
+func ConvertFirstImage(candidates []string, userAgent ...string) []byte {
+	image, _ := ConvertFirstCandidate(candidates, userAgent...)
+	return image
+}
+
+// ConvertFirstCandidate is like ConvertFirstImage, also returning the URL of the picture that converted.
+func ConvertFirstCandidate(candidates []string, userAgent ...string) ([]byte, string) {
+	for _, candidate := range candidates {
+		data, err := fetchImage(candidate, userAgent...)
+		if err != nil {
+			log.Printf("Failed to fetch image %s: %v", candidate, err)
+			continue
+		}
+
+		converted, err := convertImage(data)
+		if err != nil {
+			log.Printf("Skipping image %s: %v", candidate, err)
+			continue
+		}
+
+		return converted, candidate
+	}
+
+	return nil, ""
+}
+
+// LoadLead loads the first of pictures that can be used as the lead picture, leaving out the ones before it.
+// The pictures after it are left to be loaded if needed. It returns nil if none can be used.
+func LoadLead(pictures []Thumbnail) []Thumbnail {
+	for i := range pictures {
+		if pictures[i].Load() {
+			return pictures[i:]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package news
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestImageCandidates(t *testing.T) {
+	page := `<html><head>
+		<meta property="og:image" content="/images/share.avif">
+		<meta name="twitter:image" content="https://cdn.example.com/twitter.webp">
+		<meta property="og:image:secure_url" content="/images/share.avif">
+	</head><body><figure>
+		<picture><source srcset="/small.jpg 120w, /large.jpg 1600w, /medium.jpg 400w"></picture>
+		<img src="data:image/gif;base64,R0lGOD" data-src="//cdn.example.com/lazy.jpg">
+	</figure></body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"https://example.com/feed.jpg",
+		"https://example.com/images/share.avif",
+		"https://cdn.example.com/twitter.webp",
+		"https://example.com/medium.jpg",
+		"https://example.com/large.jpg",
+		"https://example.com/small.jpg",
+		"https://cdn.example.com/lazy.jpg",
+	}
+
+	actual := ImageCandidates(doc, "https://example.com/news/article.html", "/feed.jpg")
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestConvertFirstImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/share.avif":
+			_, _ = w.Write([]byte("\x00\x00\x00\x1cftypavif\x00\x00\x00\x00avifmif1miaf"))
+		case "/icon.png":
+			_, _ = w.Write(encodePNG(t, testPicture(16, 16)))
+		case "/photo.png":
+			_, _ = w.Write(encodePNG(t, testPicture(800, 600)))
+		}
+	}))
+	defer server.Close()
+
+	converted := ConvertFirstImage([]string{server.URL + "/share.avif", server.URL + "/icon.png", server.URL + "/photo.png"})
+	if converted == nil {
+		t.Fatal("expected the last candidate to be converted")
+	}
+
+	if ConvertFirstImage([]string{server.URL + "/share.avif"}) != nil {
+		t.Error("expected no image when no candidate converts")
+	}
+}
+
+func TestGalleryFallbacks(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/lead.png", "/second.png", "/fourth.png":
+			_, _ = w.Write(encodePNG(t, testPicture(800, 600)))
+		case "/icon.png":
+			_, _ = w.Write(encodePNG(t, testPicture(16, 16)))
+		}
+	}))
+	defer server.Close()
+
+	// The lead picture isn't the first figure, and its rendition there has another size.
+	page := `<html><body>
+		<figure><img src="/second.png"><figcaption>The harbour at dawn. (AP Photo/Jane Doe)</figcaption></figure>
+		<figure><img src="/lead.png?width=1200"><figcaption>The lead picture</figcaption></figure>
+		<figure><img src="/icon.png"><figcaption>Too small</figcaption></figure>
+		<figure><img src="/fourth.png"></figure>
+		<figure><img src="/fifth.png"></figure>
+	</body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pictures := GalleryFallbacks(doc, server.URL+"/news/article.html", server.URL+"/lead.png?width=800", 3)
+	if len(pictures) != 3 {
+		t.Fatalf("expected 3 pictures, got %d", len(pictures))
+	}
+
+	if pictures[0].Caption != "The harbour at dawn." || pictures[0].Credit != "AP Photo/Jane Doe" {
+		t.Errorf("unexpected caption %q and credit %q", pictures[0].Caption, pictures[0].Credit)
+	}
+	if pictures[1].Caption != "Too small" || pictures[2].Caption != "" {
+		t.Errorf("expected the third and fourth figures, got %q and %q", pictures[1].Caption, pictures[2].Caption)
+	}
+
+	// Fallbacks are only downloaded once they are needed.
+	if requests != 0 {
+		t.Errorf("expected no downloads yet, got %d", requests)
+	}
+
+	pictures = LoadLead(pictures[1:])
+	if len(pictures) != 1 || pictures[0].Image == nil || requests != 2 {
+		t.Errorf("expected the fourth figure to be loaded in place of the icon, got %d pictures after %d downloads", len(pictures), requests)
+	}
+}
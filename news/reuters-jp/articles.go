@@ -56,6 +56,16 @@ func (r *ReutersJP) GetArticles() ([]news.Article, error) {
 
 	articles = append(articles, temp...)
 
+	// Additional feeds from the feeds data files.
+	for _, feed := range news.FeedsFor("reuters-jp") {
+		temp, err = r.getArticles(feed.URL, feed.Topic)
+		if err != nil {
+			return nil, err
+		}
+
+		articles = append(articles, temp...)
+	}
+
 	return articles, nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -43,14 +44,23 @@ func (r *ReutersJP) getArticles(url string, topic news.Topic) ([]news.Article, e
 
 func (r *ReutersJP) createArticle(story map[string]any, topic news.Topic) (*news.Article, error) {
 	title := news.SanitizeText(story["title"].(string))
+	description, _ := story["description"].(string)
+	articlePath := story["canonical_url"]
+	articleURL := fmt.Sprintf("https://jp.reuters.com%s", articlePath)
+	skip := news.SkipEvent{
+		Source: "reuters-jp",
+		Topic:  topic.String(),
+		URL:    articleURL,
+		Title:  title,
+	}
+
 	// Compare previous articles to see if we have a duplicate.
-	if news.IsDuplicateArticle(r.oldArticleTitles, title) {
+	if match, duplicate := r.dedup.Find(title, news.SanitizeText(description)); duplicate {
+		skip.Reason, skip.MatchedTitle, skip.Score = news.SkipDuplicate, match.Title, match.Score
+		news.ReportSkip(skip)
 		return nil, nil
 	}
-	r.oldArticleTitles = append(r.oldArticleTitles, title)
 
-	articlePath := story["canonical_url"]
-	articleURL := fmt.Sprintf("https://jp.reuters.com%s", articlePath)
 	articleData, err := news.HttpGet(articleURL)
 	if err != nil {
 		return nil, err
@@ -58,15 +68,19 @@ func (r *ReutersJP) createArticle(story map[string]any, topic news.Topic) (*news
 
 	article := string(articleData)
 
-	content, locationString, err := extractArticleBody(article)
+	body, locationString, err := extractArticleBody(article)
 	if err != nil {
 		return nil, err
 	}
 
 	// Possible there is no text?
-	if len(*content) == 0 {
+	content := body.Render()
+	if len(content) == 0 {
+		skip.Reason = news.SkipNoContent
+		news.ReportSkip(skip)
 		return nil, nil
 	}
+	r.dedup.Add(title, news.Lead(content))
 
 	var location *news.Location
 	if locationString != nil {
@@ -78,59 +92,52 @@ func (r *ReutersJP) createArticle(story map[string]any, topic news.Topic) (*news
 		location = nil
 	}
 
-	// Finally get the thumbnail.
-	thumbnail, err := getThumbnail(story)
+	// Finally get the pictures.
+	pictures, err := getPictures(story)
 	if err != nil {
 		return nil, err
 	}
 
+	publishedTime, _ := story["published_time"].(string)
+	updatedTime, _ := story["updated_time"].(string)
+
 	return &news.Article{
-		Title:     title,
-		Content:   content,
-		Topic:     topic,
-		Location:  location,
-		Thumbnail: thumbnail,
+		Title:         title,
+		Source:        skip.Source,
+		URL:           skip.URL,
+		Content:       &content,
+		Body:          body,
+		Topic:         topic,
+		Location:      location,
+		Pictures:      pictures,
+		PublishedTime: news.ParseTime(publishedTime, time.RFC3339),
+		UpdatedTime:   news.ParseTime(updatedTime, time.RFC3339),
 	}, nil
 }
 
-func extractArticleBody(html string) (*string, *string, error) {
+func extractArticleBody(html string) (news.Body, *string, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var contentSlice []string
-
 	// Select the main article body div
-	doc.Find(`div.article-body-module__content__bnXL1`).Each(func(i int, section *goquery.Selection) {
-		section.Find("div.article-body-module__paragraph__Ts-yF").Each(func(j int, elem *goquery.Selection) {
-			text := strings.TrimSpace(elem.Text())
-			if text != "" {
-				contentSlice = append(contentSlice, text)
-			}
-		})
-	})
-
-	var result string
-
-	for _, content := range contentSlice {
-		result += news.SanitizeText(content)
-		result += "\n\n"
+	body := news.BoilerplateFor("reuters-jp").ParseBody(doc.Find(`div.article-body-module__content__bnXL1`).Find(`div.article-body-module__paragraph__Ts-yF, h2, h3, ul, ol`))
+	if len(body) == 0 {
+		return nil, nil, nil
 	}
 
-	result = strings.TrimSpace(result)
-
 	// Get the location
 	dateline := regexp.MustCompile(`([\[|［])(.*?)[０-９]`)
-	location := dateline.FindStringSubmatch(contentSlice[0])
+	location := dateline.FindStringSubmatch(body[0].Text)
 	if len(location) > 2 && len(location[2]) > 0 {
-		return &result, &location[2], nil
+		return body, &location[2], nil
 	}
 
-	return &result, nil, nil
+	return body, nil, nil
 }
 
-func getThumbnail(story map[string]any) (*news.Thumbnail, error) {
+func getPictures(story map[string]any) ([]news.Thumbnail, error) {
 	// Don't add Reuters logo as image
 	if story["thumbnail"].(map[string]any)["id"] != nil {
 		if story["thumbnail"].(map[string]any)["id"].(string) == "466BJJQ7PVGY5O53NZ3KL65MHM" {
@@ -140,22 +147,19 @@ func getThumbnail(story map[string]any) (*news.Thumbnail, error) {
 
 	thumbnailURL := story["thumbnail"].(map[string]any)["url"].(string)
 
-	data, err := news.HttpGet(thumbnailURL)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(data) == 0 {
+	data := news.ConvertFirstImage([]string{thumbnailURL})
+	if data == nil {
 		return nil, nil
 	}
 
-	caption := ""
-	if story["thumbnail"].(map[string]any)["caption"] != nil {
-		caption = story["thumbnail"].(map[string]any)["caption"].(string)
-	}
+	caption, _ := story["thumbnail"].(map[string]any)["caption"].(string)
 
-	return &news.Thumbnail{
-		Image:   news.ConvertImage(data),
-		Caption: news.SanitizeText(caption),
-	}, nil
+	// The photographer is credited separately, and often again at the end of the caption.
+	caption, credit := news.CaptionCredit(caption, news.ArcImageCredit(story["thumbnail"].(map[string]any)))
+
+	return []news.Thumbnail{{
+		Image:   data,
+		Caption: caption,
+		Credit:  credit,
+	}}, nil
 }
@@ -0,0 +1,29 @@
+package reutersjp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestExtractArticleBodyBoilerplate extracts the saved article body through the same path as the live articles,
+// so rules that only match an ancestor of the selected elements are exercised too.
+func TestExtractArticleBodyBoilerplate(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join("..", "testdata", "boilerplate", "reuters-jp.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := os.ReadFile(filepath.Join("..", "testdata", "boilerplate", "reuters-jp.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _, err := extractArticleBody(string(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := body.Render(); actual != strings.TrimSpace(string(expected)) {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
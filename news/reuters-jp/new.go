@@ -10,16 +10,16 @@ import (
 )
 
 type ReutersJP struct {
-	oldArticleTitles []string
+	dedup *news.Deduplicator
 	news.Source
 }
 
 //go:embed logo.jpg
 var Logo []byte
 
-func NewReuters(oldArticleTitles []string) *ReutersJP {
+func NewReuters(dedup *news.Deduplicator) *ReutersJP {
 	return &ReutersJP{
-		oldArticleTitles: oldArticleTitles,
+		dedup: dedup,
 	}
 }
 
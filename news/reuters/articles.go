@@ -57,6 +57,16 @@ func (r *Reuters) GetArticles() ([]news.Article, error) {
 
 	articles = append(articles, temp...)
 
+	// Additional feeds from the feeds data files.
+	for _, feed := range news.FeedsFor("reuters") {
+		temp, err = r.getArticles(feed.URL, feed.Topic)
+		if err != nil {
+			return nil, err
+		}
+
+		articles = append(articles, temp...)
+	}
+
 	return articles, nil
 }
 
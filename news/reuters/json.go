@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 func (r *Reuters) getArticles(url string, topic news.Topic) ([]news.Article, error) {
@@ -68,21 +69,32 @@ func (r *Reuters) getArticles(url string, topic news.Topic) ([]news.Article, err
 
 func (r *Reuters) createArticle(story map[string]any, topic news.Topic) (*news.Article, error) {
 	title := news.SanitizeText(story["title"].(string))
+	description, _ := story["description"].(string)
+	articlePath := story["url"]
+	skip := news.SkipEvent{
+		Source: "reuters",
+		Topic:  topic.String(),
+		URL:    fmt.Sprintf("https://www.reuters.com%s", articlePath),
+		Title:  title,
+	}
+
 	// Compare previous articles to see if we have a duplicate.
-	if news.IsDuplicateArticle(r.oldArticleTitles, title) {
+	if match, duplicate := r.dedup.Find(title, news.SanitizeText(description)); duplicate {
+		skip.Reason, skip.MatchedTitle, skip.Score = news.SkipDuplicate, match.Title, match.Score
+		news.ReportSkip(skip)
 		return nil, nil
 	}
-	r.oldArticleTitles = append(r.oldArticleTitles, title)
 
 	// Ignore podcasts
 	if story["section_url"] == "/podcasts/" {
+		skip.Reason = news.SkipPodcast
+		news.ReportSkip(skip)
 		return nil, nil
 	}
 
 	// The article is nested inside a "templates" list, with the data we require in the 1st index.
 	// I (Noah) refer to this as bad because it returns the web page, rather than the mobile API page.
 	// The mobile API is much easier to parse.
-	articlePath := story["url"]
 	articleURL := fmt.Sprintf("https://www.reuters.com/mobile/v1%s", articlePath)
 	articleData, err := news.HttpGet(articleURL, "ReutersNews/7.6.0 iPad8,6 iPadOS/18.1 CFNetwork/1.0 Darwin/24.1.0")
 	if err != nil {
@@ -95,45 +107,59 @@ func (r *Reuters) createArticle(story map[string]any, topic news.Topic) (*news.A
 	if err != nil {
 		var serr *json.SyntaxError
 		if errors.As(err, &serr) {
+			skip.Reason, skip.Detail = news.SkipInvalidData, err.Error()
+			news.ReportSkip(skip)
 			return nil, nil
 		}
 
 		return nil, err
 	}
 
-	content, err := parseArticle(articleJSON)
+	body, err := parseArticle(articleJSON)
 	if err != nil {
 		return nil, err
 	}
 
 	// Possible there is no text?
-	if content == nil || len(*content) == 0 {
+	content := body.Render()
+	if len(content) == 0 {
+		skip.Reason = news.SkipNoContent
+		news.ReportSkip(skip)
 		return nil, nil
 	}
+	r.dedup.Add(title, news.Lead(content))
 
 	location, err := getLocation(articleJSON)
 	if err != nil {
 		return nil, err
 	}
 
-	// Finally get the thumbnail.
-	thumbnail, err := getThumbnail(articleJSON)
+	// Finally get the pictures.
+	pictures, err := getPictures(articleJSON)
 	if err != nil {
 		return nil, err
 	}
 
+	publishedTime, _ := story["published_time"].(string)
+	updatedTime, _ := story["updated_time"].(string)
+
 	return &news.Article{
-		Title:     title,
-		Content:   content,
-		Topic:     topic,
-		Location:  location,
-		Thumbnail: thumbnail,
+		Title:         title,
+		Source:        skip.Source,
+		URL:           skip.URL,
+		Content:       &content,
+		Body:          body,
+		Topic:         topic,
+		Location:      location,
+		Pictures:      pictures,
+		PublishedTime: news.ParseTime(publishedTime, time.RFC3339),
+		UpdatedTime:   news.ParseTime(updatedTime, time.RFC3339),
 	}, nil
 }
 
-func parseArticle(root []map[string]any) (*string, error) {
+func parseArticle(root []map[string]any) (news.Body, error) {
 	// Iterate until we find the "article_detail" key
-	var ret string
+	var body news.Body
 	for _, child := range root {
 		if child["type"].(string) != "article_detail" {
 			continue
@@ -144,25 +170,36 @@ func parseArticle(root []map[string]any) (*string, error) {
 		}
 
 		for _, content := range child["data"].(map[string]any)["article"].(map[string]any)["content_elements"].([]any) {
-			if content.(map[string]any)["type"].(string) != "paragraph" {
-				continue
+			element := content.(map[string]any)
+			switch element["type"] {
+			case "paragraph":
+				body = append(body, news.ParseBodyHTML(element["content"].(string))...)
+			case "header":
+				text, _ := element["content"].(string)
+				for _, block := range news.ParseBodyHTML(text) {
+					block.Kind = news.Subheading
+					body = append(body, block)
+				}
+			case "list":
+				items, _ := element["items"].([]any)
+				for i, item := range items {
+					text, _ := item.(map[string]any)["content"].(string)
+					for _, block := range news.ParseBodyHTML(text) {
+						block.Kind = news.ListItem
+						if element["list_type"] == "ordered" {
+							block.Number = i + 1
+						}
+						body = append(body, block)
+					}
+				}
 			}
-
-			// Sanitize paragraph
-			unSanitized := content.(map[string]any)["content"].(string)
-			sanitized := news.SanitizeText(unSanitized)
-
-			ret += sanitized
-			ret += "\n\n"
 		}
 	}
 
-	ret = strings.TrimSpace(ret)
-
-	return &ret, nil
+	return body, nil
 }
 
-func getThumbnail(root []map[string]any) (*news.Thumbnail, error) {
+func getPictures(root []map[string]any) ([]news.Thumbnail, error) {
 	for _, child := range root {
 		if child["type"].(string) != "article_detail" {
 			continue
@@ -177,24 +214,22 @@ func getThumbnail(root []map[string]any) (*news.Thumbnail, error) {
 
 		thumbnailURL := child["data"].(map[string]any)["article"].(map[string]any)["thumbnail"].(map[string]any)["url"].(string)
 
-		data, err := news.HttpGet(thumbnailURL, "ReutersNews/7.6.0 iPad8,6 iPadOS/18.1 CFNetwork/1.0 Darwin/24.1.0")
-		if err != nil {
-			return nil, err
-		}
-
-		if len(data) == 0 {
+		data := news.ConvertFirstImage([]string{thumbnailURL}, "ReutersNews/7.6.0 iPad8,6 iPadOS/18.1 CFNetwork/1.0 Darwin/24.1.0")
+		if data == nil {
 			return nil, nil
 		}
 
-		caption := ""
-		if child["data"].(map[string]any)["article"].(map[string]any)["thumbnail"].(map[string]any)["caption"] != nil {
-			caption = child["data"].(map[string]any)["article"].(map[string]any)["thumbnail"].(map[string]any)["caption"].(string)
-		}
+		thumbnail := child["data"].(map[string]any)["article"].(map[string]any)["thumbnail"].(map[string]any)
+		caption, _ := thumbnail["caption"].(string)
+
+		// The photographer is credited separately, and often again at the end of the caption.
+		caption, credit := news.CaptionCredit(caption, news.ArcImageCredit(thumbnail))
 
-		return &news.Thumbnail{
-			Image:   news.ConvertImage(data),
-			Caption: news.SanitizeText(caption),
-		}, nil
+		return []news.Thumbnail{{
+			Image:   data,
+			Caption: caption,
+			Credit:  credit,
+		}}, nil
 	}
 
 	return nil, nil
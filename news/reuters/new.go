@@ -39,18 +39,18 @@ const (
 )
 
 type Reuters struct {
-	country          Country
-	oldArticleTitles []string
+	country Country
+	dedup   *news.Deduplicator
 	news.Source
 }
 
 //go:embed logo.jpg
 var Logo []byte
 
-func NewReuters(oldArticleTitles []string, countryCode uint8) *Reuters {
+func NewReuters(dedup *news.Deduplicator, countryCode uint8) *Reuters {
 	return &Reuters{
-		oldArticleTitles: oldArticleTitles,
-		country:          getCountry(countryCode),
+		dedup:   dedup,
+		country: getCountry(countryCode),
 	}
 }
 
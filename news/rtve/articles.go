@@ -50,6 +50,15 @@ func (r *RTVE) GetArticles() ([]news.Article, error) {
 	}
 	articles = append(articles, temp...)
 
+	// Additional feeds from the feeds data files.
+	for _, feed := range news.FeedsFor("rtve") {
+		temp, err = r.getArticles(feed.URL, feed.Topic)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, temp...)
+	}
+
 	return articles, nil
 }
 
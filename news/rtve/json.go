@@ -2,10 +2,17 @@ package rtve
 
 import (
 	"NewsChannel/news"
+	"bytes"
 	"encoding/json"
 	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
 )
 
+// dateLayout is the layout of the dates RTVE gives in Spanish local time.
+const dateLayout = "02-01-2006 15:04:05"
+
 // RTVEResponse represents the structure of RTVE API response
 type RTVEResponse struct {
 	Page struct {
@@ -71,48 +78,55 @@ func (r *RTVE) getArticles(url string, topic news.Topic) ([]news.Article, error)
 		title = news.SanitizeText(title)
 
 		// Check for duplicates
-		if news.IsDuplicateArticle(r.oldArticleTitles, title) {
+		skip := news.SkipEvent{
+			Source: "rtve",
+			Topic:  topic.String(),
+			URL:    rtveArticle.HTMLUrl,
+			Title:  title,
+		}
+		if match, duplicate := r.dedup.Find(title, news.SanitizeText(rtveArticle.Summary)); duplicate {
+			skip.Reason, skip.MatchedTitle, skip.Score = news.SkipDuplicate, match.Title, match.Score
+			news.ReportSkip(skip)
 			continue
 		}
-		r.oldArticleTitles = append(r.oldArticleTitles, title)
 
-		// Use the text field as content, clean HTML tags
-		content := rtveArticle.Text
-		if content == "" {
+		// Use the text field as content
+		text := rtveArticle.Text
+		if text == "" {
 			// Fall back to summary if no text
-			content = rtveArticle.Summary
+			text = rtveArticle.Summary
 		}
 
-		// Cahnge "</p><p>" to "\n\n" for better readability
-		content = strings.ReplaceAll(content, "</p><p>", "\n\n")
-
-		content = news.SanitizeText(content)
+		body := news.ParseBodyHTML(text)
+		content := body.Render()
 
 		// Skip if no content
-		if len(strings.TrimSpace(content)) == 0 {
+		if len(content) == 0 {
+			skip.Reason = news.SkipNoContent
+			news.ReportSkip(skip)
 			continue
 		}
+		r.dedup.Add(title, news.Lead(content))
 
-		// Get thumbnail - try imageSEO first, then image
-		var thumbnail *news.Thumbnail
-		imageURL := rtveArticle.ImageSEO
-		if imageURL == "" {
-			imageURL = rtveArticle.Image
-		}
-
-		if imageURL != "" {
-			thumbnail, _ = r.getThumbnail(imageURL, rtveArticle.HTMLUrl)
-		}
+		// Get pictures - try imageSEO first, then image
+		pictures := r.getPictures([]string{rtveArticle.ImageSEO, rtveArticle.Image}, rtveArticle.HTMLUrl)
 
 		// Parse location from content, category, and other topics
 		location := r.extractLocation(content, rtveArticle.MainCategory, rtveArticle.OtherTopicsName)
 
+		publishedTime, updatedTime := getTimes(rtveArticle)
+
 		article := news.Article{
-			Title:     title,
-			Content:   &content,
-			Topic:     topic,
-			Location:  location,
-			Thumbnail: thumbnail,
+			Title:         title,
+			Source:        skip.Source,
+			URL:           skip.URL,
+			Content:       &content,
+			Body:          body,
+			Topic:         topic,
+			Location:      location,
+			Pictures:      pictures,
+			PublishedTime: publishedTime,
+			UpdatedTime:   updatedTime,
 		}
 
 		articles = append(articles, article)
@@ -121,37 +135,82 @@ func (r *RTVE) getArticles(url string, topic news.Topic) ([]news.Article, error)
 	return articles, nil
 }
 
-func (r *RTVE) getThumbnail(imageURL string, articleURL string) (*news.Thumbnail, error) {
-	if imageURL == "" {
-		return nil, nil
+// getTimes returns when the article was published and last modified.
+// Only the publication date is also given as a Unix timestamp, so we use it to find the offset of the local dates.
+func getTimes(article RTVEArticle) (time.Time, time.Time) {
+	if article.PublicationDateTimestamp == 0 {
+		return time.Time{}, time.Time{}
 	}
 
-	// Ensure URL is absolute
-	if !strings.HasPrefix(imageURL, "http") {
-		if strings.HasPrefix(imageURL, "//") {
-			imageURL = "https:" + imageURL
-		} else if strings.HasPrefix(imageURL, "/") {
-			imageURL = "https://img.rtve.es" + imageURL
-		} else {
-			imageURL = "https://img.rtve.es/" + imageURL
-		}
+	published := time.UnixMilli(article.PublicationDateTimestamp)
+
+	localPublished, err := time.Parse(dateLayout, article.PublicationDate)
+	if err != nil {
+		return published, time.Time{}
 	}
 
-	data, err := news.HttpGet(imageURL)
+	localModified, err := time.Parse(dateLayout, article.ModificationDate)
 	if err != nil {
-		return nil, err
+		return published, time.Time{}
+	}
+
+	offset := localPublished.Sub(published)
+	return published, localModified.Add(-offset)
+}
+
+func (r *RTVE) getPictures(imageURLs []string, articleURL string) []news.Thumbnail {
+	var candidates []string
+	for _, imageURL := range imageURLs {
+		if imageURL == "" {
+			continue
+		}
+
+		// Ensure URL is absolute
+		if !strings.HasPrefix(imageURL, "http") {
+			if strings.HasPrefix(imageURL, "//") {
+				imageURL = "https:" + imageURL
+			} else if strings.HasPrefix(imageURL, "/") {
+				imageURL = "https://img.rtve.es" + imageURL
+			} else {
+				imageURL = "https://img.rtve.es/" + imageURL
+			}
+		}
+
+		candidates = append(candidates, imageURL)
 	}
 
-	if len(data) == 0 {
-		return nil, nil
+	image := news.ConvertFirstImage(candidates)
+	if image == nil {
+		return nil
 	}
 
-	caption := news.ExtractImageCaption(articleURL, "figcaption.figcaption span")
+	caption, credit := pictureCaption(articleURL)
+
+	return []news.Thumbnail{{
+		Image:   image,
+		Caption: caption,
+		Credit:  credit,
+	}}
+}
+
+// pictureCaption returns the caption and credit of the lead picture of an article page. The agency or photographer
+// has its own element in the caption.
+func pictureCaption(articleURL string) (string, string) {
+	if articleURL == "" {
+		return "", ""
+	}
+
+	data, err := news.HttpGet(articleURL)
+	if err != nil {
+		return "", ""
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return "", ""
+	}
 
-	return &news.Thumbnail{
-		Image:   news.ConvertImage(data),
-		Caption: news.SanitizeText(caption),
-	}, nil
+	return news.FigureCaptionCredit(doc.Find("figcaption.figcaption").First().Parent())
 }
 
 func (r *RTVE) extractLocation(text, category string, otherTopics []string) *news.Location {
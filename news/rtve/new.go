@@ -10,16 +10,16 @@ import (
 )
 
 type RTVE struct {
-	oldArticleTitles []string
+	dedup *news.Deduplicator
 	news.Source
 }
 
 //go:embed logo.jpg
 var Logo []byte
 
-func NewRTVE(oldArticleTitles []string) *RTVE {
+func NewRTVE(dedup *news.Deduplicator) *RTVE {
 	return &RTVE{
-		oldArticleTitles: oldArticleTitles,
+		dedup: dedup,
 	}
 }
 
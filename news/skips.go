@@ -0,0 +1,60 @@
+package news
+
+import (
+	"fmt"
+	"log"
+)
+
+// SkipReason is why a candidate article was left out of the news file.
+type SkipReason string
+
+const (
+	// SkipDuplicate is an article telling a story we already used.
+	SkipDuplicate SkipReason = "duplicate"
+	// SkipSameStory is an article telling the same story as another article of the same run.
+	SkipSameStory SkipReason = "same_story"
+	// SkipPodcast is a podcast episode rather than an article.
+	SkipPodcast SkipReason = "podcast"
+	// SkipNotArticle is a feed entry that isn't an article, such as a video or a live blog.
+	SkipNotArticle SkipReason = "not_article"
+	// SkipNoContent is an article whose text couldn't be found.
+	SkipNoContent SkipReason = "no_content"
+	// SkipInvalidData is an article whose page couldn't be parsed.
+	SkipInvalidData SkipReason = "invalid_data"
+	// SkipHiddenTopic is an article of a topic the country doesn't show.
+	SkipHiddenTopic SkipReason = "hidden_topic"
+)
+
+// SkipEvent describes a candidate article that was left out.
+type SkipEvent struct {
+	Reason SkipReason `json:"reason"`
+	Source string     `json:"source,omitempty"`
+	Topic  string     `json:"topic"`
+	URL    string     `json:"url,omitempty"`
+	Title  string     `json:"title"`
+	// MatchedTitle and Score are the article the candidate was found to duplicate and how similar they are.
+	MatchedTitle string  `json:"matchedTitle,omitempty"`
+	Score        float64 `json:"score,omitempty"`
+	// Detail is any further explanation, such as the error that occurred.
+	Detail string `json:"detail,omitempty"`
+}
+
+// SkipHandler receives every skip event. It is set by the generator to collect them into a report.
+var SkipHandler func(event SkipEvent)
+
+// ReportSkip logs why a candidate article was left out, and passes the event to SkipHandler.
+func ReportSkip(event SkipEvent) {
+	reason := string(event.Reason)
+	if event.MatchedTitle != "" {
+		reason += fmt.Sprintf(" of %q (similarity %.2f)", event.MatchedTitle, event.Score)
+	}
+	if event.Detail != "" {
+		reason += ": " + event.Detail
+	}
+
+	log.Printf("Skipped %q (%s, %s): %s", event.Title, event.Source, event.Topic, reason)
+
+	if SkipHandler != nil {
+		SkipHandler(event)
+	}
+}
@@ -0,0 +1,243 @@
+package news
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// Pictures are analysed at a small size, which is plenty to find the subject and keeps the search fast.
+	smartCropAnalysisSize = 128
+	// How much skin tones and saturated colours count compared to edges.
+	smartCropSkinWeight       = 1.8
+	smartCropSaturationWeight = 0.3
+	// Cutting off salient parts costs this much compared to keeping them.
+	smartCropOutsideWeight = 0.5
+	// Salient parts near the border of a crop are worth less, as they are probably cut off.
+	smartCropBorder       = 0.1
+	smartCropBorderWeight = 0.5
+	// Number of positions tried along each axis.
+	smartCropSteps = 16
+)
+
+// smartCropScales are the sizes of crop tried, relative to the largest crop of the aspect ratio.
+var smartCropScales = []float64{1, 0.9, 0.8}
+
+// smartCrop picks the part of the picture with the given aspect ratio that keeps the most of its subject.
+// Subjects are found from edges, skin tones and saturated colours, which covers faces and most news photos well
+// enough without any model.
+func (p ImagePipeline) smartCrop(source image.Image) image.Rectangle {
+	bounds := source.Bounds()
+	scale := min(float64(smartCropAnalysisSize)/float64(max(bounds.Dx(), bounds.Dy())), 1)
+	width := max(int(float64(bounds.Dx())*scale), 1)
+	height := max(int(float64(bounds.Dy())*scale), 1)
+
+	small := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.ApproxBiLinear.Scale(small, small.Bounds(), source, bounds, draw.Src, nil)
+	energy := saliency(small)
+
+	var total float64
+	for _, value := range energy {
+		total += value
+	}
+
+	full := p.cropRectangle(small.Bounds())
+	best, bestScore := full, math.Inf(-1)
+	for _, cropScale := range smartCropScales {
+		cropWidth := max(int(float64(full.Dx())*cropScale), 1)
+		cropHeight := max(int(float64(full.Dy())*cropScale), 1)
+		stepX := max((width-cropWidth)/smartCropSteps, 1)
+		stepY := max((height-cropHeight)/smartCropSteps, 1)
+
+		for y := 0; y+cropHeight <= height; y += stepY {
+			for x := 0; x+cropWidth <= width; x += stepX {
+				crop := image.Rect(x, y, x+cropWidth, y+cropHeight)
+				score := cropScore(energy, width, crop, total)
+				if score > bestScore {
+					best, bestScore = crop, score
+				}
+			}
+		}
+	}
+
+	if p.DebugDir != "" {
+		p.writeCropDebug(small, energy, best)
+	}
+
+	// Map the crop back onto the original picture, keeping the exact aspect ratio despite rounding.
+	units := min(int(float64(best.Dx())/scale+0.5)/p.AspectWidth, bounds.Dy()/p.AspectHeight, bounds.Dx()/p.AspectWidth)
+	units = max(units, 1)
+	cropWidth, cropHeight := units*p.AspectWidth, units*p.AspectHeight
+
+	x := max(min(bounds.Min.X+int(float64(best.Min.X)/scale), bounds.Max.X-cropWidth), bounds.Min.X)
+	y := max(min(bounds.Min.Y+int(float64(best.Min.Y)/scale), bounds.Max.Y-cropHeight), bounds.Min.Y)
+
+	// Pictures smaller than a single unit of the aspect ratio can't keep it, so they are used whole.
+	return image.Rect(x, y, x+cropWidth, y+cropHeight).Intersect(bounds)
+}
+
+// cropScore is the share of the picture's saliency a crop keeps, minus a share of what it cuts off.
+func cropScore(energy []float64, width int, crop image.Rectangle, total float64) float64 {
+	if total == 0 {
+		// Nothing stands out, so prefer the largest centred crop.
+		return float64(crop.Dx()*crop.Dy()) - math.Abs(float64(crop.Min.X+crop.Max.X)/2-float64(width)/2)
+	}
+
+	borderX := float64(crop.Dx()) * smartCropBorder
+	borderY := float64(crop.Dy()) * smartCropBorder
+
+	// kept is the saliency inside the crop, weighted by position, and covered is its unweighted sum.
+	// Whatever isn't covered is cut off.
+	var kept, covered float64
+	for y := crop.Min.Y; y < crop.Max.Y; y++ {
+		for x := crop.Min.X; x < crop.Max.X; x++ {
+			value := energy[y*width+x]
+			covered += value
+
+			if float64(x-crop.Min.X) < borderX || float64(crop.Max.X-1-x) < borderX ||
+				float64(y-crop.Min.Y) < borderY || float64(crop.Max.Y-1-y) < borderY {
+				value *= smartCropBorderWeight
+			}
+			kept += value
+		}
+	}
+
+	return (kept - smartCropOutsideWeight*(total-covered)) / total
+}
+
+// saliency returns how much each pixel stands out, row by row.
+func saliency(picture *image.RGBA) []float64 {
+	bounds := picture.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	luma := make([]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := picture.RGBAAt(x, y)
+			luma[y*width+x] = (0.2126*float64(c.R) + 0.7152*float64(c.G) + 0.0722*float64(c.B)) / 255
+		}
+	}
+
+	energy := make([]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*width + x
+			c := picture.RGBAAt(x, y)
+
+			// Edges are where brightness differs from the neighbouring pixels.
+			var neighbours, count float64
+			for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+				nx, ny := x+d[0], y+d[1]
+				if nx >= 0 && nx < width && ny >= 0 && ny < height {
+					neighbours += luma[ny*width+nx]
+					count++
+				}
+			}
+			edge := 0.0
+			if count > 0 {
+				edge = math.Abs(luma[i] - neighbours/count)
+			}
+
+			energy[i] = edge*4 + smartCropSkinWeight*skinTone(c, luma[i]) + smartCropSaturationWeight*saturation(c, luma[i])
+		}
+	}
+
+	return energy
+}
+
+// skinTone returns how close a colour is to the hue of skin, for pixels that are neither too dark nor too bright.
+func skinTone(c color.RGBA, luma float64) float64 {
+	if luma < 0.2 || luma > 0.9 {
+		return 0
+	}
+
+	r, g, b := float64(c.R), float64(c.G), float64(c.B)
+	length := math.Sqrt(r*r + g*g + b*b)
+	if length == 0 {
+		return 0
+	}
+
+	// Skin of all tones has about the same chromaticity, only its brightness differs.
+	dr, dg, db := r/length-0.78, g/length-0.57, b/length-0.44
+	distance := math.Sqrt(dr*dr + dg*dg + db*db)
+	return max(1-distance/0.15, 0)
+}
+
+// saturation returns the HSL saturation of a colour, for pixels that are neither too dark nor too bright.
+func saturation(c color.RGBA, luma float64) float64 {
+	if luma < 0.05 || luma > 0.9 {
+		return 0
+	}
+
+	high := float64(max(c.R, c.G, c.B)) / 255
+	low := float64(min(c.R, c.G, c.B)) / 255
+	if high == low {
+		return 0
+	}
+
+	lightness := (high + low) / 2
+	if lightness > 0.5 {
+		return (high - low) / (2 - high - low)
+	}
+
+	return (high - low) / (high + low)
+}
+
+// writeCropDebug saves the analysed picture with its saliency in green and the chosen crop in red,
+// so crops can be reviewed.
+func (p ImagePipeline) writeCropDebug(picture *image.RGBA, energy []float64, crop image.Rectangle) {
+	bounds := picture.Bounds()
+	width := bounds.Dx()
+
+	var highest float64
+	for _, value := range energy {
+		highest = max(highest, value)
+	}
+
+	overlay := image.NewRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := picture.RGBAAt(x, y)
+			if highest > 0 {
+				c.G = uint8(min(float64(c.G)+255*energy[y*width+x]/highest, 255))
+			}
+
+			onBorder := (x == crop.Min.X || x == crop.Max.X-1) && y >= crop.Min.Y && y < crop.Max.Y ||
+				(y == crop.Min.Y || y == crop.Max.Y-1) && x >= crop.Min.X && x < crop.Max.X
+			if onBorder {
+				c = color.RGBA{R: 255, A: 255}
+			}
+
+			overlay.SetRGBA(x, y, c)
+		}
+	}
+
+	err := os.MkdirAll(p.DebugDir, os.ModePerm)
+	if err != nil {
+		log.Printf("Error creating crop debug directory: %v", err)
+		return
+	}
+
+	// Name the file after the picture, so the same picture always ends up in the same file.
+	path := filepath.Join(p.DebugDir, fmt.Sprintf("crop_%x.png", sha1.Sum(picture.Pix)))
+	file, err := os.Create(path)
+	if err != nil {
+		log.Printf("Error writing crop debug file: %v", err)
+		return
+	}
+	defer file.Close()
+
+	err = png.Encode(file, overlay)
+	if err != nil {
+		log.Printf("Error writing crop debug file: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package news
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// pictureWithSubject returns a grey picture with a skin coloured, outlined disc in the given rectangle.
+func pictureWithSubject(width int, height int, subject image.Rectangle) *image.RGBA {
+	picture := image.NewRGBA(image.Rect(0, 0, width, height))
+	center := subject.Min.Add(subject.Max).Div(2)
+	radius := float64(min(subject.Dx(), subject.Dy())) / 2
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			dx, dy := float64(x-center.X), float64(y-center.Y)
+			distance := dx*dx + dy*dy
+			switch {
+			case distance < (radius-4)*(radius-4):
+				picture.SetRGBA(x, y, color.RGBA{R: 224, G: 172, B: 140, A: 255})
+			case distance < radius*radius:
+				picture.SetRGBA(x, y, color.RGBA{R: 40, G: 30, B: 20, A: 255})
+			default:
+				picture.SetRGBA(x, y, color.RGBA{R: 128, G: 128, B: 128, A: 255})
+			}
+		}
+	}
+
+	return picture
+}
+
+func TestSmartCropFindsSubject(t *testing.T) {
+	subject := image.Rect(480, 100, 580, 200)
+	picture := pictureWithSubject(600, 300, subject)
+
+	crop := DefaultImagePipeline.smartCrop(picture)
+	if !subject.In(crop) {
+		t.Errorf("expected the crop %v to contain the subject %v", crop, subject)
+	}
+
+	if crop.Dx()*3 != crop.Dy()*4 {
+		t.Errorf("expected a 4:3 crop, got %v", crop)
+	}
+
+	// A centred crop would have cut the subject in half.
+	if centred := DefaultImagePipeline.cropRectangle(picture.Bounds()); subject.In(centred) {
+		t.Errorf("the subject should be outside of the centred crop %v", centred)
+	}
+}
+
+func TestSmartCropFlatPicture(t *testing.T) {
+	picture := image.NewRGBA(image.Rect(0, 0, 800, 300))
+	for i := range picture.Pix {
+		picture.Pix[i] = 255
+	}
+
+	crop := DefaultImagePipeline.smartCrop(picture)
+	if expected := image.Rect(200, 0, 600, 300); crop != expected {
+		t.Errorf("expected the centred crop %v, got %v", expected, crop)
+	}
+}
+
+func TestSmartCropTinyPicture(t *testing.T) {
+	pipeline := DefaultImagePipeline
+	pipeline.MinWidth, pipeline.MinHeight = 0, 0
+
+	// Pictures smaller than the aspect ratio's smallest crop are used whole rather than cropped outside of them.
+	for _, bounds := range []image.Rectangle{image.Rect(0, 0, 3, 2), image.Rect(0, 0, 1, 1), image.Rect(0, 0, 2, 9)} {
+		picture := pictureWithSubject(bounds.Dx(), bounds.Dy(), bounds)
+		if crop := pipeline.smartCrop(picture); crop.Empty() || !crop.In(bounds) {
+			t.Errorf("%v: crop %v is outside of the picture", bounds, crop)
+		}
+
+		_, err := pipeline.Process(encodePNG(t, picture))
+		if err != nil {
+			t.Errorf("%v: %v", bounds, err)
+		}
+	}
+}
+
+func TestSmartCropDebug(t *testing.T) {
+	pipeline := DefaultImagePipeline
+	pipeline.DebugDir = t.TempDir()
+
+	_, err := pipeline.Process(encodePNG(t, pictureWithSubject(400, 200, image.Rect(20, 50, 120, 150))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(pipeline.DebugDir, "crop_*.png"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected a crop debug picture, got %v: %v", files, err)
+	}
+
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, format, err := image.Decode(bytes.NewReader(data)); err != nil || format != "png" {
+		t.Errorf("expected a PNG, got %q: %v", format, err)
+	}
+}
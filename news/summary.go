@@ -0,0 +1,250 @@
+package news
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// SummaryRule summarizes the articles of a source and topic that are longer than Length UTF-16 units.
+// An empty source or topic matches every source or topic.
+type SummaryRule struct {
+	Source string `xml:"Source,attr"`
+	Topic  string `xml:"Topic,attr"`
+	Length int    `xml:"Length,attr"`
+}
+
+// Summaries are the rules deciding which articles are summarized. The first rule matching an article is used.
+var Summaries []SummaryRule
+
+// Validate checks that the topic is filled by the source, or by any source if the rule has none, and that the length
+// leaves room for more than a sentence. The feeds must be loaded first.
+func (r SummaryRule) Validate() error {
+	if r.Topic != "" {
+		topic, err := ParseTopic(r.Topic)
+		if err != nil {
+			return err
+		}
+		if r.Source != "" && !slices.Contains(SourceTopics(r.Source), topic) {
+			return fmt.Errorf("topic %q is not filled by %s", r.Topic, r.Source)
+		}
+		if !KnownTopic(topic) {
+			return fmt.Errorf("topic %q is not filled by any source", r.Topic)
+		}
+	}
+	if r.Length < 100 {
+		return errors.New("summaries must be at least 100 units long")
+	}
+
+	return nil
+}
+
+// SummaryLength returns the length articles of a source and topic are summarized to, or zero if they aren't.
+func SummaryLength(source string, topic Topic) int {
+	for _, rule := range Summaries {
+		if (rule.Source == "" || rule.Source == source) && (rule.Topic == "" || rule.Topic == topic.String()) {
+			return rule.Length
+		}
+	}
+
+	return 0
+}
+
+// Weights of what makes a sentence worth keeping in a summary.
+const (
+	positionWeight  = 0.4
+	frequencyWeight = 0.3
+	headlineWeight  = 0.3
+)
+
+// sentence is a sentence of a body considered for a summary.
+type sentence struct {
+	block int
+	text  string
+	score float64
+}
+
+// splitSentences splits text after the punctuation ending each sentence, much like sentenceEnd finds it. Sentences
+// keep their punctuation and any closing quote, but not the spaces between them.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		if !isSentenceEnd(r) {
+			continue
+		}
+
+		for i < len(text) {
+			next, size := utf8.DecodeRuneInString(text[i:])
+			if !isClosing(next) && !isSentenceEnd(next) {
+				break
+			}
+			i += size
+		}
+
+		// A quote ending in punctuation doesn't end the sentence it is part of, as in "“No!” he said."
+		next, _ := utf8.DecodeRuneInString(text[i:])
+		following, _ := utf8.DecodeRuneInString(strings.TrimLeftFunc(text[i:], unicode.IsSpace))
+		if (i == len(text) || unicode.IsSpace(next) || r >= 0x3000) && !unicode.IsLower(following) {
+			if sentence := strings.TrimSpace(text[start:i]); sentence != "" {
+				sentences = append(sentences, sentence)
+			}
+			start = i
+		}
+	}
+
+	if rest := strings.TrimSpace(text[start:]); rest != "" {
+		sentences = append(sentences, rest)
+	}
+
+	return sentences
+}
+
+// units returns the length of text in UTF-16 units.
+func units(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
+
+// Summarize shortens body to at most length UTF-16 units once rendered. The lead paragraph is always kept whole,
+// and the rest of the summary is made of the sentences that score best for coming early in the article, using the
+// article's most frequent words and sharing words with the headline. Sentences keep their order, and subheadings
+// are left out as the sections they introduce are cut. It returns body itself if it is short enough.
+func Summarize(body Body, headline string, length int, language string) Body {
+	if units(body.Render()) <= length {
+		return body
+	}
+
+	lead := 0
+	for lead < len(body) && body[lead].Kind != Paragraph {
+		lead++
+	}
+	if lead == len(body) {
+		return body
+	}
+
+	var sentences []sentence
+	frequencies := make(map[string]int)
+	for i, block := range body[lead+1:] {
+		if block.Kind == Subheading {
+			continue
+		}
+
+		texts := []string{block.Text}
+		if block.Kind != ListItem {
+			texts = splitSentences(block.Text)
+		}
+		for _, text := range texts {
+			sentences = append(sentences, sentence{block: lead + 1 + i, text: text})
+		}
+	}
+	for _, block := range body {
+		for _, token := range Tokenize(block.Text, language) {
+			frequencies[token]++
+		}
+	}
+
+	mostFrequent := 1
+	for _, frequency := range frequencies {
+		mostFrequent = max(mostFrequent, frequency)
+	}
+
+	headlineTokens := make(map[string]bool)
+	for _, token := range Tokenize(headline, language) {
+		headlineTokens[token] = true
+	}
+
+	for i := range sentences {
+		tokens := Tokenize(sentences[i].text, language)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		frequency, shared := 0.0, make(map[string]bool)
+		for _, token := range tokens {
+			frequency += float64(frequencies[token]) / float64(mostFrequent)
+			if headlineTokens[token] {
+				shared[token] = true
+			}
+		}
+
+		score := positionWeight * (1 - float64(i)/float64(len(sentences)))
+		score += frequencyWeight * frequency / float64(len(tokens))
+		if len(headlineTokens) != 0 {
+			score += headlineWeight * float64(len(shared)) / float64(len(headlineTokens))
+		}
+		sentences[i].score = score
+	}
+
+	order := make([]int, len(sentences))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sentences[order[a]].score > sentences[order[b]].score
+	})
+
+	chosen := make([]bool, len(sentences))
+	for _, i := range order {
+		chosen[i] = true
+		if units(summaryBody(body, lead, sentences, chosen, language).Render()) > length {
+			chosen[i] = false
+		}
+	}
+
+	return summaryBody(body, lead, sentences, chosen, language)
+}
+
+// summaryBody returns the lead followed by the chosen sentences, joined back into the blocks they come from.
+func summaryBody(body Body, lead int, sentences []sentence, chosen []bool, language string) Body {
+	separator := " "
+	if language == "ja" {
+		separator = ""
+	}
+
+	summary := Body{body[lead]}
+	previous := -1
+	for i, sentence := range sentences {
+		if !chosen[i] {
+			continue
+		}
+
+		if sentence.block == previous {
+			summary[len(summary)-1].Text += separator + sentence.text
+			continue
+		}
+
+		block := body[sentence.block]
+		block.Text = sentence.text
+		summary = append(summary, block)
+		previous = sentence.block
+	}
+
+	return summary
+}
+
+// SummarizeArticle summarizes an article with a structured body to at most length units, recording its original
+// length. It returns whether the article was summarized.
+func SummarizeArticle(article *Article, length int, language string) bool {
+	if article.Body == nil {
+		return false
+	}
+
+	original := units(article.Body.Render())
+	if original <= length {
+		return false
+	}
+
+	summary := Summarize(article.Body, article.Title, length, language)
+	content := summary.Render()
+	article.OriginalLength = original
+	article.Body = summary
+	article.Content = &content
+	return true
+}
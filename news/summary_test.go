@@ -0,0 +1,114 @@
+package news
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var longBody = Body{
+	{Kind: Paragraph, Text: "The city council approved the new harbour bridge on Monday after years of debate."},
+	{Kind: Paragraph, Text: "The weather was mild. The bridge will cost 300 million euros. Several cafés opened nearby."},
+	{Kind: Subheading, Text: "Reactions"},
+	{Kind: Paragraph, Text: "The mayor said the bridge would connect the harbour to the old town. A local band played."},
+	{Kind: ListItem, Text: "Construction starts in spring."},
+	{Kind: ListItem, Text: "Parking prices rise."},
+	{Kind: Paragraph, Text: "Opponents say the harbour bridge is too expensive and plan to appeal the council decision."},
+}
+
+func TestSummarize(t *testing.T) {
+	headline := "Council approves harbour bridge"
+	length := 200
+
+	summary := Summarize(longBody, headline, length, "en")
+	if units(summary.Render()) > length {
+		t.Errorf("summary is over %d units: %q", length, summary.Render())
+	}
+	if !reflect.DeepEqual(summary[0], longBody[0]) {
+		t.Errorf("expected the lead to be kept, got %+v", summary[0])
+	}
+
+	rendered := summary.Render()
+	for _, kept := range []string{"The bridge will cost 300 million euros.", "connect the harbour to the old town"} {
+		if !strings.Contains(rendered, kept) {
+			t.Errorf("expected %q in the summary %q", kept, rendered)
+		}
+	}
+	for _, dropped := range []string{"Reactions", "A local band played.", "The weather was mild."} {
+		if strings.Contains(rendered, dropped) {
+			t.Errorf("expected %q to be left out of the summary %q", dropped, rendered)
+		}
+	}
+
+	// Sentences stay in the order of the article.
+	if strings.Index(rendered, "300 million") > strings.Index(rendered, "old town") {
+		t.Errorf("sentences are out of order in %q", rendered)
+	}
+
+	if short := Summarize(longBody[:2], headline, 1000, "en"); !reflect.DeepEqual(short, longBody[:2]) {
+		t.Errorf("expected a short body to be kept as it is, got %+v", short)
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"It costs 3.5 million. “Too much!” he said. Done", []string{"It costs 3.5 million.", "“Too much!” he said.", "Done"}},
+		{"橋が完成した。開通は来月の予定だ。", []string{"橋が完成した。", "開通は来月の予定だ。"}},
+	}
+
+	for _, test := range tests {
+		if actual := splitSentences(test.text); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%q: expected %q, got %q", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestSummaryLength(t *testing.T) {
+	defer func(rules []SummaryRule) {
+		Summaries = rules
+	}(Summaries)
+	Summaries = []SummaryRule{
+		{Source: "ap", Topic: "sports", Length: 500},
+		{Source: "ap", Length: 1500},
+		{Topic: "business", Length: 1000},
+	}
+
+	tests := []struct {
+		source   string
+		topic    Topic
+		expected int
+	}{
+		{"ap", Sports, 500},
+		{"ap", Business, 1500},
+		{"reuters", Business, 1000},
+		{"reuters", Sports, 0},
+	}
+
+	for _, test := range tests {
+		if actual := SummaryLength(test.source, test.topic); actual != test.expected {
+			t.Errorf("%s %s: expected %d, got %d", test.source, test.topic, test.expected, actual)
+		}
+	}
+
+	if err := (SummaryRule{Topic: "Weather news", Length: 500}).Validate(); err == nil {
+		t.Error("expected an invalid topic key to be rejected")
+	}
+	if err := (SummaryRule{Topic: "sport", Length: 500}).Validate(); err == nil {
+		t.Error("expected a topic no source fills to be rejected")
+	}
+}
+
+func TestSummarizeArticle(t *testing.T) {
+	content := longBody.Render()
+	article := Article{Title: "Council approves harbour bridge", Content: &content, Body: longBody}
+
+	if !SummarizeArticle(&article, 300, "en") {
+		t.Fatal("expected the article to be summarized")
+	}
+	if article.OriginalLength != units(content) || *article.Content != article.Body.Render() {
+		t.Errorf("unexpected article %d %q", article.OriginalLength, *article.Content)
+	}
+}
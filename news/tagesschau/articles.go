@@ -2,6 +2,7 @@ package tagesschau
 
 import (
 	"NewsChannel/news"
+	"strings"
 )
 
 func (r *Tagesschau) GetArticles() ([]news.Article, error) {
@@ -56,6 +57,22 @@ func (r *Tagesschau) GetArticles() ([]news.Article, error) {
 
 	articles = append(articles, temp...)
 
+	// Additional feeds from the feeds data files.
+	for _, feed := range news.FeedsFor("tagesschau") {
+		// Search results are listed under their own key.
+		storyKey := "news"
+		if strings.Contains(feed.URL, "/api2u/search") {
+			storyKey = "searchResults"
+		}
+
+		temp, err = r.getArticles(feed.URL, feed.Topic, storyKey)
+		if err != nil {
+			return nil, err
+		}
+
+		articles = append(articles, temp...)
+	}
+
 	return articles, nil
 }
 
@@ -4,7 +4,7 @@ import (
 	"NewsChannel/news"
 	"encoding/json"
 	"errors"
-	"strings"
+	"time"
 )
 
 func (r *Tagesschau) getArticles(url string, topic news.Topic, storyKey string) ([]news.Article, error) {
@@ -25,18 +25,29 @@ func (r *Tagesschau) getArticles(url string, topic news.Topic, storyKey string)
 	var articles []news.Article
 	for _, story := range stories {
 		title := news.SanitizeText(story.(map[string]any)["title"].(string))
+		firstSentence, _ := story.(map[string]any)["firstSentence"].(string)
+		articleURL, _ := story.(map[string]any)["details"].(string)
+		skip := news.SkipEvent{
+			Source: "tagesschau",
+			Topic:  topic.String(),
+			URL:    articleURL,
+			Title:  title,
+		}
+
 		// Compare previous articles to see if we have a duplicate.
-		if news.IsDuplicateArticle(r.oldArticleTitles, title) {
+		if match, duplicate := r.dedup.Find(title, news.SanitizeText(firstSentence)); duplicate {
+			skip.Reason, skip.MatchedTitle, skip.Score = news.SkipDuplicate, match.Title, match.Score
+			news.ReportSkip(skip)
 			continue
 		}
-		r.oldArticleTitles = append(r.oldArticleTitles, title)
 
 		// Ignore non-articles
 		if story.(map[string]any)["type"].(string) != "story" {
+			skip.Reason = news.SkipNotArticle
+			news.ReportSkip(skip)
 			continue
 		}
 
-		articleURL := story.(map[string]any)["details"].(string)
 		articleData, err := news.HttpGet(articleURL)
 		if err != nil {
 			return nil, err
@@ -48,39 +59,52 @@ func (r *Tagesschau) getArticles(url string, topic news.Topic, storyKey string)
 		if err != nil {
 			var serr *json.SyntaxError
 			if errors.As(err, &serr) {
+				skip.Reason, skip.Detail = news.SkipInvalidData, err.Error()
+				news.ReportSkip(skip)
 				continue
 			}
 
 			return nil, err
 		}
 
-		content, err := parseArticle(articleJSON)
+		body, err := parseArticle(articleJSON)
 		if err != nil {
 			return nil, err
 		}
 
 		// Possible there is no text?
-		if len(*content) == 0 {
+		content := body.Render()
+		if len(content) == 0 {
+			skip.Reason = news.SkipNoContent
+			news.ReportSkip(skip)
 			continue
 		}
+		r.dedup.Add(title, news.Lead(content))
 
 		location, err := getLocation(articleJSON)
 		if err != nil {
 			return nil, err
 		}
 
-		// Finally get the thumbnail.
-		thumbnail, err := getThumbnail(articleJSON)
+		// Finally get the pictures.
+		pictures, err := getPictures(articleJSON)
 		if err != nil {
 			return nil, err
 		}
 
+		// Tagesschau only gives a single date per story.
+		date, _ := story.(map[string]any)["date"].(string)
+
 		article := news.Article{
-			Title:     title,
-			Content:   content,
-			Topic:     topic,
-			Location:  location,
-			Thumbnail: thumbnail,
+			Title:         title,
+			Source:        skip.Source,
+			URL:           skip.URL,
+			Content:       &content,
+			Body:          body,
+			Topic:         topic,
+			Location:      location,
+			Pictures:      pictures,
+			PublishedTime: news.ParseTime(date, time.RFC3339),
 		}
 
 		articles = append(articles, article)
@@ -90,73 +114,99 @@ func (r *Tagesschau) getArticles(url string, topic news.Topic, storyKey string)
 	return articles, nil
 }
 
-func parseArticle(root map[string]any) (*string, error) {
+func parseArticle(root map[string]any) (news.Body, error) {
 	// Iterate through text content
-	var ret string
+	var body news.Body
 	for _, content := range root["content"].([]any) {
 		if !allowedTypes[content.(map[string]any)["type"].(string)] {
 			continue
 		}
 
-		var unSanitized string
-		if content.(map[string]any)["type"].(string) == "quotation" {
-			unSanitized = content.(map[string]any)["quotation"].(map[string]any)["text"].(string)
-		} else {
-			unSanitized = content.(map[string]any)["value"].(string)
+		switch content.(map[string]any)["type"].(string) {
+		case "quotation":
+			text := content.(map[string]any)["quotation"].(map[string]any)["text"].(string)
+			for _, block := range news.ParseBodyHTML(text) {
+				block.Kind = news.Quote
+				body = append(body, block)
+			}
+		case "headline":
+			for _, block := range news.ParseBodyHTML(content.(map[string]any)["value"].(string)) {
+				block.Kind = news.Subheading
+				body = append(body, block)
+			}
+		default:
+			body = append(body, news.ParseBodyHTML(content.(map[string]any)["value"].(string))...)
 		}
+	}
 
-		sanitized := news.SanitizeText(unSanitized)
+	return body, nil
+}
 
-		ret += sanitized
-		ret += "\n\n"
+// getPictures returns the teaser image of an article followed by the pictures of its image and gallery boxes.
+func getPictures(root map[string]any) ([]news.Thumbnail, error) {
+	var images []map[string]any
+	if teaserImage, ok := root["teaserImage"].(map[string]any); ok {
+		images = append(images, teaserImage)
 	}
 
-	ret = strings.TrimSpace(ret)
-
-	return &ret, nil
-}
+	contents, _ := root["content"].([]any)
+	for _, content := range contents {
+		content, _ := content.(map[string]any)
+		switch content["type"] {
+		case "image":
+			if image, ok := content["image"].(map[string]any); ok {
+				images = append(images, image)
+			}
+		case "gallery":
+			gallery, _ := content["gallery"].([]any)
+			for _, image := range gallery {
+				if image, ok := image.(map[string]any); ok {
+					images = append(images, image)
+				}
+			}
+		}
+	}
 
-func getThumbnail(root map[string]any) (*news.Thumbnail, error) {
-	if root["teaserImage"] == nil {
-		return nil, nil
+	var pictures []news.Thumbnail
+	for _, image := range images {
+		picture := lazyPicture(image)
+		if picture != nil {
+			pictures = append(pictures, *picture)
+		}
 	}
 
-	image := root["teaserImage"].(map[string]any)
+	// Only the lead picture is converted now, the others if it can't be used.
+	pictures = news.LoadLead(pictures)
+	return pictures[:min(len(pictures), 1+news.PictureFallbacks)], nil
+}
+
+// lazyPicture returns the picture of an image box, which isn't downloaded until it is loaded.
+func lazyPicture(image map[string]any) *news.Thumbnail {
 	if image["imageVariants"] == nil {
-		return nil, nil
+		return nil
 	}
 
 	// Ignore Tagesschau logo
 	if image["alttext"] != nil {
 		if image["alttext"].(string) == "Globus auf blauem Hintergrund mit tagesschau-Schriftzug" {
-			return nil, nil
+			return nil
 		}
 	}
 
 	acceptedThumbnails := []string{
 		"1x1-840", "1x1-640", "1x1-432", "1x1-256", "1x1-144",
 	}
-	var thumbnailURL string
+	var thumbnailURLs []string
 
-	// Get highest res 1x1 ratio image URL
+	// Try the 1x1 ratio images from the highest res down
 	for _, thumbnail := range acceptedThumbnails {
 		if image["imageVariants"].(map[string]any)[thumbnail] != nil {
-			thumbnailURL = image["imageVariants"].(map[string]any)[thumbnail].(string)
-			break
+			thumbnailURLs = append(thumbnailURLs, image["imageVariants"].(map[string]any)[thumbnail].(string))
 		}
 	}
 
-	if thumbnailURL == "" {
-		return nil, nil
-	}
-
-	data, err := news.HttpGet(thumbnailURL)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(data) == 0 {
-		return nil, nil
+	if len(thumbnailURLs) == 0 {
+		return nil
 	}
 
 	caption := ""
@@ -166,10 +216,10 @@ func getThumbnail(root map[string]any) (*news.Thumbnail, error) {
 		caption = image["alttext"].(string)
 	}
 
-	return &news.Thumbnail{
-		Image:   news.ConvertImage(data),
-		Caption: news.SanitizeText(caption),
-	}, nil
+	credit, _ := image["copyright"].(string)
+
+	picture := news.LazyThumbnail(thumbnailURLs, news.SanitizeText(caption), news.SanitizeText(credit))
+	return &picture
 }
 
 func getLocation(root map[string]any) (*news.Location, error) {
@@ -7,16 +7,16 @@ import (
 )
 
 type Tagesschau struct {
-	oldArticleTitles []string
+	dedup *news.Deduplicator
 	news.Source
 }
 
 //go:embed logo.jpg
 var Logo []byte
 
-func NewTagesschau(oldArticleTitles []string) *Tagesschau {
+func NewTagesschau(dedup *news.Deduplicator) *Tagesschau {
 	return &Tagesschau{
-		oldArticleTitles: oldArticleTitles,
+		dedup: dedup,
 	}
 }
 
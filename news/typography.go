@@ -0,0 +1,219 @@
+package news
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Typography are the quotes, dashes, ellipses and spacing of a language, using forms the console's font can show.
+type Typography struct {
+	// quotes and innerQuotes are the opening and closing quotation marks, and the ones used for quotes within quotes.
+	quotes      [2]string
+	innerQuotes [2]string
+	// dash separates parts of a sentence, replacing a hyphen with spaces around it. Empty leaves hyphens as they are.
+	dash     string
+	ellipsis string
+	// spaceBefore is put before : ; ! ? and inside guillemets, as French does. The font has no narrow no-break space,
+	// so French uses the wider one. Other languages have no space there.
+	spaceBefore string
+	// fullWidth converts ASCII punctuation next to Japanese text to its full-width form.
+	fullWidth bool
+}
+
+var typographies = map[string]*Typography{
+	"en": {quotes: [2]string{"“", "”"}, innerQuotes: [2]string{"‘", "’"}, dash: " — ", ellipsis: "…"},
+	"de": {quotes: [2]string{"„", "“"}, innerQuotes: [2]string{"‚", "‘"}, dash: " – ", ellipsis: "…"},
+	"fr": {quotes: [2]string{"«", "»"}, innerQuotes: [2]string{"“", "”"}, dash: " – ", ellipsis: "…", spaceBefore: " "},
+	"es": {quotes: [2]string{"«", "»"}, innerQuotes: [2]string{"“", "”"}, dash: " – ", ellipsis: "…"},
+	"it": {quotes: [2]string{"«", "»"}, innerQuotes: [2]string{"“", "”"}, dash: " – ", ellipsis: "…"},
+	"nl": {quotes: [2]string{"“", "”"}, innerQuotes: [2]string{"‘", "’"}, dash: " – ", ellipsis: "…"},
+	"ja": {quotes: [2]string{"「", "」"}, innerQuotes: [2]string{"『", "』"}, ellipsis: "……", fullWidth: true},
+}
+
+// TypographyForLanguage returns the typography of a language tag, falling back to English.
+func TypographyForLanguage(language string) *Typography {
+	if typography, ok := typographies[language]; ok {
+		return typography
+	}
+
+	return typographies["en"]
+}
+
+var (
+	ellipses            = regexp.MustCompile(`\.{3,}|…+|・{3,}`)
+	spacedHyphens       = regexp.MustCompile(` +(?:-{1,2}|–|—) +`)
+	doubleHyphens       = regexp.MustCompile(`(\pL)--(\pL)`)
+	spacesBefore        = regexp.MustCompile(`(\S)[ \x{00A0}\x{202F}]*([:;!?]+)([\s»”)]|$)`)
+	spacesAfterOpening  = regexp.MustCompile(`«[ \x{00A0}\x{202F}]*`)
+	spacesBeforeClosing = regexp.MustCompile(`[ \x{00A0}\x{202F}]*»`)
+	halfWidthKana       = regexp.MustCompile(`[\x{FF61}-\x{FF9F}]+`)
+)
+
+// fullWidthPunctuation are the ASCII marks that take their full-width form next to Japanese text.
+var fullWidthPunctuation = map[rune]rune{
+	'!': '！', '?': '？', ':': '：', ';': '；', '(': '（', ')': '）',
+}
+
+// Apply returns text with the quotes, dashes, ellipses and spacing of the language. It expects sanitized text.
+func (t *Typography) Apply(text string) string {
+	text = ellipses.ReplaceAllString(text, t.ellipsis)
+	if t.dash != "" {
+		text = t.replaceDashes(text)
+		text = doubleHyphens.ReplaceAllString(text, "$1"+strings.TrimSpace(t.dash)+"$2")
+	}
+
+	text = t.applyQuotes(text)
+
+	if t.spaceBefore != "" {
+		text = spacesBefore.ReplaceAllStringFunc(text, func(match string) string {
+			parts := spacesBefore.FindStringSubmatch(match)
+			return parts[1] + t.spaceBefore + parts[2] + parts[3]
+		})
+		text = spacesAfterOpening.ReplaceAllString(text, "«"+t.spaceBefore)
+		text = spacesBeforeClosing.ReplaceAllString(text, t.spaceBefore+"»")
+	} else if !t.fullWidth {
+		text = spacesBefore.ReplaceAllString(text, "$1$2$3")
+	}
+
+	if t.fullWidth {
+		text = halfWidthKana.ReplaceAllStringFunc(text, norm.NFKC.String)
+		text = widenPunctuation(text)
+	}
+
+	return repeatedSpaces.ReplaceAllString(text, " ")
+}
+
+// replaceDashes replaces hyphens and dashes with spaces around them by the dash of the language, except between
+// numbers, as in sports scores.
+func (t *Typography) replaceDashes(text string) string {
+	var builder strings.Builder
+	last := 0
+	for _, match := range spacedHyphens.FindAllStringIndex(text, -1) {
+		before, _ := utf8.DecodeLastRuneInString(text[:match[0]])
+		after, _ := utf8.DecodeRuneInString(text[match[1]:])
+		if unicode.IsDigit(before) && unicode.IsDigit(after) {
+			continue
+		}
+
+		builder.WriteString(text[last:match[0]])
+		builder.WriteString(t.dash)
+		last = match[1]
+	}
+	builder.WriteString(text[last:])
+
+	return builder.String()
+}
+
+// isJapanese reports whether r is written in Japanese, including the long vowel mark and full-width punctuation.
+func isJapanese(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) ||
+		(r >= 0x3000 && r <= 0x30FF) || (r >= 0xFF01 && r <= 0xFF60)
+}
+
+// widenPunctuation converts the punctuation next to Japanese text to full width, which doesn't need spaces.
+func widenPunctuation(text string) string {
+	runes := []rune(text)
+	for i, r := range runes {
+		wide, ok := fullWidthPunctuation[r]
+		if !ok {
+			continue
+		}
+
+		previous := i > 0 && isJapanese(runes[i-1])
+		next := i+1 < len(runes) && isJapanese(runes[i+1])
+		if previous || next {
+			runes[i] = wide
+		}
+	}
+
+	return string(runes)
+}
+
+// opensQuote reports whether a quotation mark following r opens a quote.
+func opensQuote(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("([{—–-/«„“‘‚「『", r)
+}
+
+// applyQuotes replaces quotation marks with the ones of the language. Whether a mark opens or closes a quote is
+// told by what surrounds it or, in Japanese, by whether a quote is open. Single marks between letters, or closing
+// no quote, are apostrophes.
+func (t *Typography) applyQuotes(text string) string {
+	runes := []rune(text)
+	var builder strings.Builder
+	doubleOpen, singleOpen := false, false
+	for i, r := range runes {
+		previous, next := ' ', ' '
+		if i > 0 {
+			previous = runes[i-1]
+		}
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch r {
+		case '"', '“', '”', '„', '«', '»':
+			var opening bool
+			switch {
+			case r == '„' || r == '«':
+				opening = true
+			case r == '»':
+				opening = false
+			case t.fullWidth:
+				// Japanese has no spaces to tell where quotes start.
+				opening = r == '“' || (r == '"' && !doubleOpen)
+			case opensQuote(previous) && unicode.IsSpace(next):
+				// A mark standing on its own closes the quote that is open, if any.
+				opening = !doubleOpen
+			default:
+				opening = opensQuote(previous)
+			}
+
+			if opening {
+				builder.WriteString(t.quotes[0])
+			} else {
+				builder.WriteString(t.quotes[1])
+			}
+			doubleOpen = opening
+		case '\'', '‘', '’', '‚':
+			switch {
+			case unicode.IsLetter(previous) && unicode.IsLetter(next):
+				builder.WriteRune('’')
+			case r != '’' && opensQuote(previous) && !unicode.IsSpace(next):
+				builder.WriteString(t.innerQuotes[0])
+				singleOpen = true
+			case singleOpen:
+				builder.WriteString(t.innerQuotes[1])
+				singleOpen = false
+			default:
+				builder.WriteRune('’')
+			}
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+// ApplyArticle applies the typography to the headline, text and picture captions of an article.
+func (t *Typography) ApplyArticle(article *Article) {
+	article.Title = t.Apply(article.Title)
+	if article.Content != nil {
+		content := t.Apply(*article.Content)
+		article.Content = &content
+	}
+
+	// Pictures can be shared with other articles, so they are copied rather than changed.
+	if article.Pictures != nil {
+		pictures := make([]Thumbnail, len(article.Pictures))
+		for i, picture := range article.Pictures {
+			picture.Caption = t.Apply(picture.Caption)
+			pictures[i] = picture
+		}
+		article.Pictures = pictures
+	}
+}
@@ -0,0 +1,44 @@
+package news
+
+import "testing"
+
+func TestTypography(t *testing.T) {
+	tests := []struct {
+		language string
+		text     string
+		expected string
+	}{
+		{"en", `He said "it's over" - and left...`, "He said “it’s over” — and left…"},
+		{"en", `The 'Iron Lady' won 3 - 1.`, "The ‘Iron Lady’ won 3 - 1."},
+		{"en", "Is it true ? Yes !", "Is it true? Yes!"},
+		{"de", `Er sagte: "Das ist vorbei." Dann ging er.`, "Er sagte: „Das ist vorbei.“ Dann ging er."},
+		{"de", "„Wir schaffen das“, sagte sie - und ging.", "„Wir schaffen das“, sagte sie – und ging."},
+		{"fr", `Il a dit : "C'est fini!" Vraiment?`, "Il a dit : « C’est fini ! » Vraiment ?"},
+		{"fr", "Rendez-vous à 14:30 sur https://france24.com", "Rendez-vous à 14:30 sur https://france24.com"},
+		{"es", `El presidente dijo "no habrá cambios".`, "El presidente dijo «no habrá cambios»."},
+		{"it", `Il ministro: "Nessun rinvio"`, "Il ministro: «Nessun rinvio»"},
+		{"ja", `首相は"検討する"と述べた...(ﾛｲﾀｰ)`, "首相は「検討する」と述べた……（ロイター）"},
+		{"ja", "日本の(GDP)は 3.5% (前年比)", "日本の（GDP）は 3.5% （前年比）"},
+	}
+
+	for _, test := range tests {
+		actual := TypographyForLanguage(test.language).Apply(test.text)
+		if actual != test.expected {
+			t.Errorf("%s %q: expected %q, got %q", test.language, test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestTypographyArticle(t *testing.T) {
+	content := `"Yes" - he said.`
+	pictures := []Thumbnail{{Caption: `The "new" bridge...`}}
+	article := Article{Title: `Council says "yes"`, Content: &content, Pictures: pictures}
+
+	TypographyForLanguage("en").ApplyArticle(&article)
+	if article.Title != "Council says “yes”" || *article.Content != "“Yes” — he said." || article.Pictures[0].Caption != "The “new” bridge…" {
+		t.Errorf("unexpected article %q %q %q", article.Title, *article.Content, article.Pictures[0].Caption)
+	}
+	if pictures[0].Caption != `The "new" bridge...` {
+		t.Error("expected the original pictures to be unchanged")
+	}
+}
@@ -1,27 +1,41 @@
 package news
 
 import (
-	"bufio"
-	"bytes"
 	"fmt"
 	"html"
-	"image/jpeg"
 	"io"
 	"log"
-	"math"
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
+)
 
-	"github.com/PuerkitoBio/goquery"
-	"github.com/pmezard/go-difflib/difflib"
-	"golang.org/x/image/draw"
+// retryDelay is how long to wait before trying a request again.
+var retryDelay = 1 * time.Second
 
-	"image"
-	_ "image/jpeg"
-	_ "image/png"
-)
+// sendWithRetries sends a request up to five times, until it gets a response with one of the expected status codes.
+// It returns the last response or error.
+func sendWithRetries(client *http.Client, req *http.Request, expected ...int) (*http.Response, error) {
+	var resp *http.Response
+	var err error
+	for i := 0; i < 5; i++ {
+		if i > 0 {
+			time.Sleep(retryDelay)
+		}
+
+		resp, err = client.Do(req)
+		if err == nil && slices.Contains(expected, resp.StatusCode) {
+			break
+		}
+		if err == nil && i < 4 {
+			_ = resp.Body.Close()
+		}
+	}
+
+	return resp, err
+}
 
 func HttpGet(url string, userAgent ...string) ([]byte, error) {
 	client := &http.Client{}
@@ -35,14 +49,7 @@ func HttpGet(url string, userAgent ...string) ([]byte, error) {
 		req.Header.Set("User-Agent", userAgent[0])
 	}
 
-	var resp *http.Response
-	for i := 0; i < 5; i++ {
-		resp, err = client.Do(req)
-		if err == nil && resp.StatusCode == http.StatusOK {
-			break
-		}
-		time.Sleep(1 * time.Second)
-	}
+	resp, err := sendWithRetries(client, req, http.StatusOK)
 	if err != nil {
 		return nil, fmt.Errorf("HTTP request to %v failed: %v", url, err)
 	} else if resp.StatusCode != http.StatusOK {
@@ -63,45 +70,23 @@ func HttpGet(url string, userAgent ...string) ([]byte, error) {
 	return body, nil
 }
 
-func IsDuplicateArticle(previousArticles []string, currentArticle string) bool {
-	for _, previousArticle := range previousArticles {
-		diff := difflib.NewMatcher(strings.Split(currentArticle, ""), strings.Split(previousArticle, ""))
-		if diff.QuickRatio() >= 0.85 {
-			return true
-		}
+// ParseTime parses a timestamp given by a source, trying each layout in order.
+// It returns the zero time if none of them match, which the generator replaces with the current time.
+func ParseTime(value string, layouts ...string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
 	}
 
-	return false
-}
-
-func ConvertImage(data []byte) []byte {
-	origImage, _, err := image.Decode(bytes.NewReader(data))
-	if err != nil {
-		return nil
-	}
-
-	origBounds := origImage.Bounds()
-	origWidth := origBounds.Dx()
-	origHeight := origBounds.Dy()
-
-	scaleFactor := math.Max(
-		float64(200)/float64(origWidth),
-		float64(200)/float64(origHeight),
-	)
-
-	resizeWidth := int(math.Ceil(float64(origWidth) * scaleFactor))
-	resizeHeight := int(math.Ceil(float64(origHeight) * scaleFactor))
-
-	resizedImage := image.NewRGBA(image.Rect(0, 0, resizeWidth, resizeHeight))
-	draw.BiLinear.Scale(resizedImage, resizedImage.Bounds(), origImage, origBounds, draw.Over, nil)
-
-	var outputImgWriter bytes.Buffer
-	err = jpeg.Encode(bufio.NewWriter(&outputImgWriter), resizedImage, nil)
-	if err != nil {
-		return nil
+	for _, layout := range layouts {
+		parsed, err := time.Parse(layout, value)
+		if err == nil {
+			return parsed
+		}
 	}
 
-	return outputImgWriter.Bytes()
+	log.Printf("Failed to parse time %q", value)
+	return time.Time{}
 }
 
 func SanitizeText(content string) string {
@@ -163,28 +148,3 @@ func SanitizeText(content string) string {
 
 	return strings.TrimSpace(content)
 }
-
-func ExtractImageCaption(articleURL string, find string) string {
-	if articleURL == "" {
-		return ""
-	}
-
-	data, err := HttpGet(articleURL)
-	if err != nil {
-		return ""
-	}
-
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(data)))
-	if err != nil {
-		return ""
-	}
-
-	caption := doc.Find(find).Text()
-	if caption != "" {
-		caption = strings.ReplaceAll(caption, "Quelle:", "")
-		caption = strings.TrimSpace(caption)
-		return caption
-	}
-
-	return ""
-}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"NewsChannel/news"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// reportDir is where the reports of every run are saved.
+const reportDir = "./reports"
+
+// reportTimeFormat is how the time a run started is written in the names of its reports.
+const reportTimeFormat = "2006-01-02_15-04-05"
+
+// skipReportRetention is how long skip reports are kept.
+const skipReportRetention = 7 * 24 * time.Hour
+
+// SkipReport lists every candidate article that was left out during a run, so editors can tell why a story didn't run.
+type SkipReport struct {
+	GeneratedAt time.Time        `json:"generatedAt"`
+	Skipped     []SkippedArticle `json:"skipped"`
+}
+
+// SkippedArticle is a skip event along with the news file it happened in.
+type SkippedArticle struct {
+	Country  string `json:"country"`
+	Language string `json:"language"`
+	news.SkipEvent
+}
+
+// collect returns a skip handler adding events to the report for the given country.
+// If breadcrumbs is set, events are also added as Sentry breadcrumbs so that they come with any error reported.
+func (r *SkipReport) collect(countryConfig CountryConfig, breadcrumbs bool) func(event news.SkipEvent) {
+	return func(event news.SkipEvent) {
+		r.Skipped = append(r.Skipped, SkippedArticle{
+			Country:   countryConfig.Name,
+			Language:  countryConfig.Language,
+			SkipEvent: event,
+		})
+
+		if breadcrumbs {
+			sentry.AddBreadcrumb(&sentry.Breadcrumb{
+				Category: "skip",
+				Message:  fmt.Sprintf("Skipped %q: %s", event.Title, event.Reason),
+				Level:    sentry.LevelInfo,
+				Data: map[string]any{
+					"country":      countryConfig.Name,
+					"source":       event.Source,
+					"topic":        event.Topic,
+					"url":          event.URL,
+					"matchedTitle": event.MatchedTitle,
+					"score":        event.Score,
+				},
+			})
+		}
+	}
+}
+
+// write saves the report into dir, named after the time the run started.
+func (r *SkipReport) write(dir string) error {
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("skips_%s.json", r.GeneratedAt.Format(reportTimeFormat)))
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return pruneReports(dir, "skips", r.GeneratedAt.Add(-skipReportRetention))
+}
+
+// pruneReports removes the reports in dir named after prefix whose run started before cutoff.
+func pruneReports(dir string, prefix string, cutoff time.Time) error {
+	paths, err := filepath.Glob(filepath.Join(dir, prefix+"_*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), prefix+"_"), ".json")
+		generatedAt, err := time.ParseInLocation(reportTimeFormat, name, cutoff.Location())
+		if err != nil || !generatedAt.Before(cutoff) {
+			continue
+		}
+
+		err = os.Remove(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
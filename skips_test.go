@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSkipReportRetention(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"skips_2025-05-20_12-00-00.json", "skips_2025-05-30_12-00-00.json", "truncations_2025-05-20_12-00-00.json", "skips_notes.json"} {
+		err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report := &SkipReport{GeneratedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)}
+	err := report.write(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	// Only skip reports older than the retention are removed.
+	expected := []string{"skips_2025-05-30_12-00-00.json", "skips_2025-06-01_12-00-00.json", "skips_notes.json", "truncations_2025-05-20_12-00-00.json"}
+	if !slices.Equal(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"NewsChannel/news"
 	"NewsChannel/news/ansa"
 	"NewsChannel/news/ap"
 	"NewsChannel/news/france24"
@@ -12,7 +13,9 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
+	"slices"
 	"time"
 )
 
@@ -28,29 +31,43 @@ type Source struct {
 	CopyrightOffset uint32
 }
 
+// sourceNames are the sources setSource knows. Every other name gets Reuters.
+var sourceNames = []string{"rtve", "ansa", "france24", "nos", "tagesschau", "reuters-jp", "ap"}
+
+// topicsOfSource returns the topics a country's source can fill, so countries can be checked without creating it.
+func topicsOfSource(sourceName string) []news.Topic {
+	if !slices.Contains(sourceNames, sourceName) {
+		sourceName = "reuters"
+	}
+
+	return news.SourceTopics(sourceName)
+}
+
 func (n *News) setSource(sourceName string) {
+	n.sourceName = sourceName
 	switch sourceName {
 	case "rtve":
-		rtveSource := rtve.NewRTVE(n.oldArticleTitles)
+		rtveSource := rtve.NewRTVE(n.dedup)
 		n.source = rtveSource
 	case "ansa":
-		ansaSource := ansa.NewAnsa(n.oldArticleTitles)
+		ansaSource := ansa.NewAnsa(n.dedup)
 		n.source = ansaSource
 	case "france24":
-		franceSource := france24.NewFrance24(n.oldArticleTitles)
+		franceSource := france24.NewFrance24(n.dedup)
 		n.source = franceSource
 	case "nos":
-		nosSource := nos.NewNos(n.oldArticleTitles)
+		nosSource := nos.NewNos(n.dedup)
 		n.source = nosSource
 	case "tagesschau":
-		tagesschauSource := tagesschau.NewTagesschau(n.oldArticleTitles)
+		tagesschauSource := tagesschau.NewTagesschau(n.dedup)
 		n.source = tagesschauSource
 	case "reuters-jp":
-		n.source = reutersjp.NewReuters(n.oldArticleTitles)
+		n.source = reutersjp.NewReuters(n.dedup)
 	case "ap":
-		n.source = ap.NewAP(n.oldArticleTitles)
+		n.source = ap.NewAP(n.dedup)
 	default:
-		n.source = reuters.NewReuters(n.oldArticleTitles, n.currentCountryCode)
+		n.sourceName = "reuters"
+		n.source = reuters.NewReuters(n.dedup, n.currentCountryCode)
 	}
 }
 
@@ -61,12 +78,115 @@ func (n *News) GetNewsArticles() error {
 		return err
 	}
 
+	language := n.GetLanguageTag()
+	if classifier := news.ClassifierForLanguage(language); classifier != nil && news.Classification.Enabled {
+		n.classifyTopics(classifier)
+	}
+
+	// Leave out the topics the country hides.
+	n.articles = slices.DeleteFunc(n.articles, func(article news.Article) bool {
+		if _, shown := n.topicIndex(article.Topic); shown {
+			return false
+		}
+
+		news.ReportSkip(news.SkipEvent{
+			Reason: news.SkipHiddenTopic,
+			Source: article.Source,
+			Topic:  article.Topic.String(),
+			URL:    article.URL,
+			Title:  article.Title,
+		})
+		return true
+	})
+
+	// Keep a single article per story across topics.
+	var shared *news.SharedStories
+	if sharedStories != nil {
+		if sharedStories[language] == nil {
+			sharedStories[language] = news.NewSharedStories(language)
+		}
+		shared = sharedStories[language]
+	}
+	n.articles = news.NewStoryClusterer(language, n.topicPriority, shared).Cluster(n.articles, n.countryName)
+
+	// Summarize the articles that have a summary rule, use the quotes, dashes and spacing of the language, replace
+	// the characters the console's font can't show, and finally cut the text down to the limits.
+	typography := news.TypographyForLanguage(language)
+	coverage := news.CoverageForLanguage(language)
+	for i := range n.articles {
+		if length := news.SummaryLength(n.sourceName, n.articles[i].Topic); length != 0 {
+			summarized := news.SummarizeArticle(&n.articles[i], length, language)
+			if truncations != nil {
+				truncations.recordSummary(n.sourceName, n.articles[i], summarized)
+			}
+		}
+
+		typography.ApplyArticle(&n.articles[i])
+		replacements := coverage.NormalizeArticle(&n.articles[i])
+		if len(replacements) != 0 {
+			log.Printf("Replaced characters in %q: %v", n.articles[i].Title, replacements)
+		}
+
+		truncated := news.ApplyLimits(&n.articles[i], language)
+		fields := []string{news.FieldHeadline, news.FieldBody}
+		if n.articles[i].Location != nil {
+			fields = append(fields, news.FieldLocationName)
+		}
+		for range n.articles[i].Pictures {
+			fields = append(fields, news.FieldCaption)
+		}
+		n.recordTruncations(truncated, fields...)
+	}
+
+	// Every article of this hour needs an ID within the hour's range.
+	if len(n.articles) >= articleIDsPerHour {
+		log.Printf("Dropping %d articles as only %d fit in an hour", len(n.articles)-articleIDsPerHour+1, articleIDsPerHour-1)
+		n.articles = n.articles[:articleIDsPerHour-1]
+	}
+
 	// Save articles to file for inspection (Debug)
 	// n.debugSaveArticles()
 
 	return nil
 }
 
+// classifyTopics moves articles from feeds covering several topics to the one the classifier is confident they
+// belong to. Articles from other feeds keep their topic, but are logged when the classifier confidently disagrees.
+func (n *News) classifyTopics(classifier *news.TopicClassifier) {
+	for i, article := range n.articles {
+		topic, confidence := classifier.ClassifyArticle(article)
+		if topic == article.Topic || confidence < news.Classification.Threshold {
+			continue
+		}
+
+		if len(article.AlternativeTopics) != 0 {
+			log.Printf("Moved %q from %s to %s (confidence %.2f)", article.Title, article.Topic, topic, confidence)
+			n.articles[i].Topic = topic
+		} else {
+			log.Printf("%q was filed under %s but reads like %s (confidence %.2f)", article.Title, article.Topic, topic, confidence)
+		}
+	}
+}
+
+// recordTruncations counts the texts of fields written for the source, the ones listed in truncated having been
+// truncated. A field is listed once for every text.
+func (n *News) recordTruncations(truncated []string, fields ...string) {
+	for _, field := range fields {
+		index := slices.Index(truncated, field)
+		if index != -1 {
+			truncated = slices.Delete(truncated, index, index+1)
+		}
+		n.recordTruncation(field, index != -1)
+	}
+}
+
+// recordTruncation counts a text of field written for the source.
+func (n *News) recordTruncation(field string, truncated bool) {
+	if truncations != nil {
+		truncations.record(n.sourceName, field, truncated)
+	}
+}
+
 func (n *News) MakeSourceTable() {
 	n.Header.SourceTableOffset = n.GetCurrentSize()
 
@@ -127,10 +247,10 @@ func (n *News) debugSaveArticles() {
 		HasImage     bool   `json:"hasImage"`
 		ImageSize    int    `json:"imageSize"`
 		ImageCaption string `json:"imageCaption"`
+		Pictures     int    `json:"pictures"`
 	}
 
 	var debugArticles []DebugArticle
-	topicNames := []string{"National", "International", "Sports", "Entertainment", "Business", "Science", "Technology"}
 
 	for _, article := range n.articles {
 		var content string
@@ -147,18 +267,18 @@ func (n *News) debugSaveArticles() {
 			location = "No location"
 		}
 
-		var topicName string
-		if int(article.Topic) < len(topicNames) {
-			topicName = topicNames[article.Topic]
-		} else {
-			topicName = fmt.Sprintf("Topic_%d", article.Topic)
+		topicName := article.Topic.String()
+		if index, shown := n.topicIndex(article.Topic); shown {
+			topicName = n.topicLabels[index-1].Name
 		}
 
 		var hasImage bool
 		var imageSize int
-		if article.Thumbnail != nil {
+		var imageCaption string
+		if len(article.Pictures) != 0 {
 			hasImage = true
-			imageSize = len(article.Thumbnail.Image)
+			imageSize = len(article.Pictures[0].Image)
+			imageCaption = article.Pictures[0].Caption
 		}
 
 		debugArticles = append(debugArticles, DebugArticle{
@@ -168,7 +288,8 @@ func (n *News) debugSaveArticles() {
 			Location:     location,
 			HasImage:     hasImage,
 			ImageSize:    imageSize,
-			ImageCaption: article.Thumbnail.Caption,
+			ImageCaption: imageCaption,
+			Pictures:     len(article.Pictures),
 		})
 	}
 
@@ -0,0 +1,219 @@
+package store
+
+import (
+	"NewsChannel/news"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Retention is how long articles are kept after the hour they were generated for.
+const Retention = 48 * time.Hour
+
+// lockTimeout is how long we wait for another process to release the database.
+const lockTimeout = 1 * time.Minute
+
+// BoltStore is a Store backed by a bbolt database.
+// Each country and language has its own bucket, in which articles are keyed by generation time then ID,
+// so time windows are a single range scan.
+//
+// bbolt locks the file for as long as it is open, so the database is only opened for each operation. Concurrent
+// generators, and validation, then wait for a single transaction rather than for a whole run.
+type BoltStore struct {
+	path     string
+	readOnly bool
+}
+
+// Open opens or creates the database at path.
+func Open(path string) (*BoltStore, error) {
+	s := &BoltStore{path: path}
+	err := s.update(func(tx *bolt.Tx) error {
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// OpenReadOnly opens the existing database at path for reading. It only waits for writers, not other readers.
+func OpenReadOnly(path string) (*BoltStore, error) {
+	_, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open article store %s: %w", path, err)
+	}
+
+	return &BoltStore{path: path, readOnly: true}, nil
+}
+
+func (s *BoltStore) update(fn func(tx *bolt.Tx) error) error {
+	if s.readOnly {
+		return errors.New("the article store was opened read only")
+	}
+
+	db, err := bolt.Open(s.path, 0666, &bolt.Options{Timeout: lockTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open article store %s: %w", s.path, err)
+	}
+
+	err = db.Update(fn)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (s *BoltStore) view(fn func(tx *bolt.Tx) error) error {
+	db, err := bolt.Open(s.path, 0666, &bolt.Options{Timeout: lockTimeout, ReadOnly: s.readOnly})
+	if err != nil {
+		return fmt.Errorf("failed to open article store %s: %w", s.path, err)
+	}
+
+	err = db.View(fn)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// pictureReferences returns what is kept of the pictures of an article. Only the current hour's pictures are
+// written, so their data is left out of the store.
+func pictureReferences(pictures []news.Thumbnail) []Picture {
+	var references []Picture
+	for _, picture := range pictures {
+		// Pictures that were never downloaded have nothing to refer to.
+		if len(picture.Image) == 0 {
+			continue
+		}
+
+		hash := sha256.Sum256(picture.Image)
+		references = append(references, Picture{
+			Hash:    hex.EncodeToString(hash[:]),
+			Caption: picture.Caption,
+			Credit:  picture.Credit,
+		})
+	}
+
+	return references
+}
+
+func bucketName(key Key) []byte {
+	return []byte(fmt.Sprintf("%03d_%d", key.CountryCode, key.LanguageCode))
+}
+
+// recordKey orders records by generation time, then by ID.
+// Times before the Unix epoch, such as the zero time, are clamped to it.
+func recordKey(generatedAt time.Time, id uint32) []byte {
+	key := make([]byte, 12)
+	binary.BigEndian.PutUint64(key, uint64(max(generatedAt.Unix(), 0)))
+	binary.BigEndian.PutUint32(key[8:], id)
+	return key
+}
+
+func timeKey(t time.Time) []byte {
+	return recordKey(t, 0)
+}
+
+func (s *BoltStore) PutHour(key Key, hour time.Time, articles []Article) error {
+	return s.update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(bucketName(key))
+		if err != nil {
+			return err
+		}
+
+		// Remove what a previous run for this hour wrote, as well as anything past retention.
+		err = deleteRange(bucket, timeKey(hour), timeKey(hour.Add(time.Hour)))
+		if err != nil {
+			return err
+		}
+
+		err = deleteRange(bucket, nil, timeKey(hour.Add(-Retention)))
+		if err != nil {
+			return err
+		}
+
+		for _, article := range articles {
+			article.GeneratedAt = hour
+			if article.Article.Pictures != nil {
+				article.Pictures = pictureReferences(article.Article.Pictures)
+				article.Article.Pictures = nil
+			}
+
+			data, err := json.Marshal(article)
+			if err != nil {
+				return err
+			}
+
+			err = bucket.Put(recordKey(hour, article.ID), data)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *BoltStore) Window(key Key, from time.Time, to time.Time) ([]Article, error) {
+	var articles []Article
+	err := s.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketName(key))
+		if bucket == nil {
+			return nil
+		}
+
+		end := timeKey(to)
+		cursor := bucket.Cursor()
+		for k, v := cursor.Seek(timeKey(from)); k != nil && string(k) < string(end); k, v = cursor.Next() {
+			var article Article
+			err := json.Unmarshal(v, &article)
+			if err != nil {
+				return fmt.Errorf("corrupt article %x in %s: %w", k, bucketName(key), err)
+			}
+
+			articles = append(articles, article)
+		}
+
+		return nil
+	})
+
+	return articles, err
+}
+
+// Close does nothing, as the database is only open during operations.
+func (s *BoltStore) Close() error {
+	return nil
+}
+
+// deleteRange deletes every record between start (inclusive, nil for the beginning) and end (exclusive).
+func deleteRange(bucket *bolt.Bucket, start []byte, end []byte) error {
+	// Collect the keys first, as modifying the bucket invalidates the cursor.
+	var keys [][]byte
+	cursor := bucket.Cursor()
+
+	k, _ := cursor.First()
+	if start != nil {
+		k, _ = cursor.Seek(start)
+	}
+
+	for ; k != nil && string(k) < string(end); k, _ = cursor.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+
+	for _, k := range keys {
+		err := bucket.Delete(k)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package store
+
+import (
+	"NewsChannel/news"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func openTestStore(t *testing.T) *BoltStore {
+	s, err := Open(filepath.Join(t.TempDir(), "articles.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	return s
+}
+
+func makeArticles(titles ...string) []Article {
+	var articles []Article
+	for i, title := range titles {
+		content := "Content of " + title
+		articles = append(articles, Article{
+			ID: uint32(i + 1),
+			Article: news.Article{
+				Title:   title,
+				Content: &content,
+				Topic:   news.Sports,
+			},
+		})
+	}
+
+	return articles
+}
+
+func TestBoltStoreWindow(t *testing.T) {
+	s := openTestStore(t)
+	key := Key{CountryCode: 110, LanguageCode: 1}
+	start := time.Date(2024, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	for hour := 0; hour < 30; hour++ {
+		err := s.PutHour(key, start.Add(time.Duration(hour)*time.Hour), makeArticles("first", "second"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Another country must not show up in the window.
+	err := s.PutHour(Key{CountryCode: 1, LanguageCode: 0}, start.Add(20*time.Hour), makeArticles("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	articles, err := s.Window(key, start.Add(5*time.Hour), start.Add(29*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(articles) != 48 {
+		t.Fatalf("expected 48 articles, got %d", len(articles))
+	}
+	if !articles[0].GeneratedAt.Equal(start.Add(5 * time.Hour)) {
+		t.Errorf("expected the oldest article first, got %v", articles[0].GeneratedAt)
+	}
+	if *articles[0].Article.Content != "Content of first" || articles[0].Article.Topic != news.Sports {
+		t.Errorf("article was not stored in full: %+v", articles[0].Article)
+	}
+}
+
+func TestBoltStoreReplacesHour(t *testing.T) {
+	s := openTestStore(t)
+	key := Key{CountryCode: 110, LanguageCode: 1}
+	hour := time.Date(2024, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	err := s.PutHour(key, hour, makeArticles("first", "second", "third"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.PutHour(key, hour, makeArticles("replacement"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	articles, err := s.Window(key, hour, hour.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(articles) != 1 || articles[0].Article.Title != "replacement" {
+		t.Errorf("expected only the replacement article, got %+v", articles)
+	}
+}
+
+func TestBoltStoreRetention(t *testing.T) {
+	s := openTestStore(t)
+	key := Key{CountryCode: 110, LanguageCode: 1}
+	hour := time.Date(2024, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	err := s.PutHour(key, hour, makeArticles("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.PutHour(key, hour.Add(Retention+time.Hour), makeArticles("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	articles, err := s.Window(key, time.Time{}, hour.Add(100*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(articles) != 1 || articles[0].Article.Title != "new" {
+		t.Errorf("expected the old article to be pruned, got %+v", articles)
+	}
+}
+
+func TestBoltStoreReadsTopicIndices(t *testing.T) {
+	s := openTestStore(t)
+	key := Key{CountryCode: 110, LanguageCode: 1}
+	hour := time.Date(2024, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	// Articles stored before topics were keys hold the index of a built-in topic.
+	err := s.update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(bucketName(key))
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(recordKey(hour, 1), []byte(`{"id":1,"article":{"Title":"Old","Topic":2}}`))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.PutHour(key, hour.Add(time.Hour), []Article{{ID: 2, Article: news.Article{Title: "New", Topic: "weather"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	articles, err := s.Window(key, hour, hour.Add(2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(articles) != 2 || articles[0].Article.Topic != news.Sports || articles[1].Article.Topic != "weather" {
+		t.Errorf("unexpected articles %+v", articles)
+	}
+}
+
+func TestBoltStoreConcurrentStores(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "articles.db")
+	key := Key{CountryCode: 110, LanguageCode: 1}
+	hour := time.Date(2024, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	// A generator keeps its store for the whole run, which must not lock others out.
+	first, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+
+	second, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+
+	err = first.PutHour(key, hour, makeArticles("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = second.PutHour(key, hour.Add(time.Hour), makeArticles("second"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := OpenReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	articles, err := reader.Window(key, hour, hour.Add(2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 2 {
+		t.Errorf("expected 2 articles, got %d", len(articles))
+	}
+
+	if reader.PutHour(key, hour, nil) == nil {
+		t.Error("expected a read only store to refuse writes")
+	}
+}
+
+func TestBoltStoreLeavesOutPictureData(t *testing.T) {
+	s := openTestStore(t)
+	key := Key{CountryCode: 110, LanguageCode: 1}
+	hour := time.Date(2024, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	articles := makeArticles("first")
+	articles[0].Article.Pictures = []news.Thumbnail{{Image: []byte{1, 2, 3}, Caption: "A bridge", Credit: "AP"}}
+	err := s.PutHour(key, hour, articles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := s.Window(key, hour, hour.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Picture{Hash: "039058c6f2c0cb492c533b0a4d14ef77cc0f78abccced5287d84a1a2011cfb81", Caption: "A bridge", Credit: "AP"}
+	if len(stored) != 1 || stored[0].Article.Pictures != nil || len(stored[0].Pictures) != 1 || stored[0].Pictures[0] != expected {
+		t.Errorf("unexpected pictures %+v %+v", stored[0].Article.Pictures, stored[0].Pictures)
+	}
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"NewsChannel/news"
+	"time"
+)
+
+// Key identifies the news files of a country in a language.
+type Key struct {
+	CountryCode  uint8
+	LanguageCode uint8
+}
+
+// Article is an article as it was written into a news file.
+type Article struct {
+	// ID is the article number used in the news file.
+	ID uint32 `json:"id"`
+	// GeneratedAt is the start of the hour the news file was generated for.
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Timestamp is the value written into the timestamp table.
+	Timestamp uint32       `json:"timestamp"`
+	Article   news.Article `json:"article"`
+	// Pictures refer to the picture written into the file with the article, whose data isn't stored.
+	Pictures []Picture `json:"pictures,omitempty"`
+}
+
+// Picture refers to a picture of an article by the SHA-256 hash of its data.
+type Picture struct {
+	Hash    string `json:"hash"`
+	Caption string `json:"caption,omitempty"`
+	Credit  string `json:"credit,omitempty"`
+}
+
+// Store keeps the articles used in past news files, as every file has to list the articles of the last day.
+type Store interface {
+	// PutHour replaces the articles generated for the hour starting at hour.
+	PutHour(key Key, hour time.Time, articles []Article) error
+	// Window returns the articles generated between from (inclusive) and to (exclusive), oldest first.
+	Window(key Key, from time.Time, to time.Time) ([]Article, error)
+	Close() error
+}
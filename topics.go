@@ -2,10 +2,9 @@ package main
 
 import (
 	"NewsChannel/news"
-	"encoding/json"
-	"fmt"
-	"os"
+	"NewsChannel/store"
 	"sort"
+	"time"
 	"unicode/utf16"
 )
 
@@ -15,54 +14,51 @@ type Topic struct {
 	TimestampTableOffset uint32
 }
 
-// Timestamp handles the time an article was obtained.
+// Timestamp handles the time an article was published.
 type Timestamp struct {
 	Time          uint32
 	ArticleNumber uint32
 }
 
-// NewsCache contains the bare minimum for articles we grabbed in the past.
-type NewsCache struct {
-	ID        uint32     `json:"id"`
-	Timestamp uint32     `json:"timestamp"`
-	Topic     news.Topic `json:"topic"`
-	Title     string     `json:"title"`
-}
-
 // ReadNewsCache creates the topic table as well as the timestamp table for articles.
 // This is quite an annoying job as for some reason it needs to make the timestamp table for every single article, even ones
-// from past hours. Due to this we are required to cache what articles we used.
-func (n *News) ReadNewsCache() {
-	topics := n.GetTopicsForLanguage()
-	topicsLength := len(topics) + 1
+// from past hours. Due to this we are required to store what articles we used. Articles of topics the country hides
+// are left out.
+func (n *News) ReadNewsCache() error {
+	topicsLength := len(n.topicLabels) + 1
 
 	n.topics = make([]Topic, topicsLength)
 	n.timestamps = make([][]Timestamp, topicsLength)
 
-	for i := 0; i < 24; i++ {
-		// Don't process the cache for the current hour.
-		if i == n.currentHour {
-			continue
-		}
+	// The previous 23 hours, as the current hour is what we are generating.
+	hour := currentHourStart()
+	articles, err := n.articleStore.Window(n.storeKey(), hour.Add(-23*time.Hour), hour)
+	if err != nil {
+		return err
+	}
 
-		var _articles []NewsCache
-		data, err := os.ReadFile(fmt.Sprintf("./cache/cache_%d_%d_%d.news", i, n.currentCountryCode, n.currentLanguageCode))
-		if err != nil {
-			continue
+	n.dedup = news.NewDeduplicator(n.GetLanguageTag())
+	for _, article := range articles {
+		lead := ""
+		if article.Article.Content != nil {
+			lead = news.Lead(*article.Article.Content)
 		}
 
-		err = json.Unmarshal(data, &_articles)
-		checkError(err)
+		n.dedup.Add(article.Article.Title, lead)
 
-		for _, article := range _articles {
-			n.topics[article.Topic+1].NumberOfArticles++
-			n.oldArticleTitles = append(n.oldArticleTitles, article.Title)
-			n.timestamps[article.Topic+1] = append(n.timestamps[article.Topic+1], Timestamp{
-				Time:          article.Timestamp,
-				ArticleNumber: article.ID,
-			})
+		index, shown := n.topicIndex(article.Article.Topic)
+		if !shown {
+			continue
 		}
+
+		n.topics[index].NumberOfArticles++
+		n.timestamps[index] = append(n.timestamps[index], Timestamp{
+			Time:          article.Timestamp,
+			ArticleNumber: article.ID,
+		})
 	}
+
+	return nil
 }
 
 func (n *News) MakeTopicTable() {
@@ -70,8 +66,7 @@ func (n *News) MakeTopicTable() {
 	n.Header.TopicTableOffset = n.GetCurrentSize()
 	n.Topics = n.topics
 
-	topics := n.GetTopicsForLanguage()
-	topicsLength := len(topics) + 1
+	topicsLength := len(n.topicLabels) + 1
 	n.Header.NumberOfTopics = uint32(topicsLength)
 
 	// Now we copy all our data into the struct
@@ -88,9 +83,9 @@ func (n *News) MakeTopicTable() {
 		n.Timestamps = append(n.Timestamps, tempTimestamps...)
 	}
 
-	for i, topic := range topics {
+	for i, label := range n.topicLabels {
 		n.Topics[i+1].TextOffset = n.GetCurrentSize()
-		n.TopicText = append(n.TopicText, utf16.Encode([]rune(topic))...)
+		n.TopicText = append(n.TopicText, utf16.Encode([]rune(label.Name))...)
 		n.TopicText = append(n.TopicText, 0)
 		for n.GetCurrentSize()%4 != 0 {
 			n.TopicText = append(n.TopicText, uint16(0))
@@ -98,28 +93,23 @@ func (n *News) MakeTopicTable() {
 	}
 }
 
-// WriteNewsCache writes the found articles for the current hour.
-func (n *News) WriteNewsCache() {
-	// Order everything into the NewsCache struct
-	var cache []NewsCache
+// WriteNewsCache stores the found articles for the current hour, replacing any from a previous run this hour.
+func (n *News) WriteNewsCache() error {
+	var articles []store.Article
 	for i, article := range n.articles {
-		cache = append(cache, NewsCache{
+		articles = append(articles, store.Article{
 			ID:        n.Articles[i].ID,
-			Timestamp: fixTime(currentTime),
-			Topic:     article.Topic,
-			Title:     article.Title,
+			Timestamp: n.Articles[i].PublishedTime,
+			Article:   article,
 		})
 	}
 
-	// Encode NewsCache array
-	data, err := json.Marshal(cache)
-	checkError(err)
+	return n.articleStore.PutHour(n.storeKey(), currentHourStart(), articles)
+}
 
-	// Now write file
-	err = os.MkdirAll("./cache", os.ModePerm)
-	if !os.IsExist(err) {
-		checkError(err)
+func (n *News) storeKey() store.Key {
+	return store.Key{
+		CountryCode:  n.currentCountryCode,
+		LanguageCode: n.currentLanguageCode,
 	}
-	err = os.WriteFile(fmt.Sprintf("./cache/cache_%d_%d_%d.news", n.currentHour, n.currentCountryCode, n.currentLanguageCode), data, 0666)
-	checkError(err)
 }
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"NewsChannel/news"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+	"unicode/utf16"
+)
+
+// TruncationReport counts, for each source and text field, how many texts were written and how many of them had to
+// be truncated, so that limits can be tuned.
+type TruncationReport struct {
+	GeneratedAt time.Time                                `json:"generatedAt"`
+	Sources     map[string]map[string]*TruncationCounter `json:"sources"`
+	// Summaries count the articles of each source that could be summarized.
+	Summaries map[string]*SummaryCounter `json:"summaries,omitempty"`
+}
+
+type TruncationCounter struct {
+	Texts     int `json:"texts"`
+	Truncated int `json:"truncated"`
+}
+
+// SummaryCounter holds how many articles were summarized, and their lengths in UTF-16 units before and after.
+type SummaryCounter struct {
+	Articles       int `json:"articles"`
+	Summarized     int `json:"summarized"`
+	OriginalLength int `json:"originalLength"`
+	SummaryLength  int `json:"summaryLength"`
+}
+
+// record counts a text of field written for source.
+func (r *TruncationReport) record(source string, field string, truncated bool) {
+	if r.Sources == nil {
+		r.Sources = make(map[string]map[string]*TruncationCounter)
+	}
+	if r.Sources[source] == nil {
+		r.Sources[source] = make(map[string]*TruncationCounter)
+	}
+	if r.Sources[source][field] == nil {
+		r.Sources[source][field] = &TruncationCounter{}
+	}
+
+	r.Sources[source][field].Texts++
+	if truncated {
+		r.Sources[source][field].Truncated++
+	}
+}
+
+// recordSummary counts an article of source that could be summarized.
+func (r *TruncationReport) recordSummary(source string, article news.Article, summarized bool) {
+	if r.Summaries == nil {
+		r.Summaries = make(map[string]*SummaryCounter)
+	}
+	if r.Summaries[source] == nil {
+		r.Summaries[source] = &SummaryCounter{}
+	}
+
+	counter := r.Summaries[source]
+	counter.Articles++
+	if summarized {
+		counter.Summarized++
+		counter.OriginalLength += article.OriginalLength
+		counter.SummaryLength += len(utf16.Encode([]rune(*article.Content)))
+	}
+}
+
+// log prints how often each field of each source was truncated.
+func (r *TruncationReport) log() {
+	for _, source := range slices.Sorted(maps.Keys(r.Sources)) {
+		for _, field := range slices.Sorted(maps.Keys(r.Sources[source])) {
+			counter := r.Sources[source][field]
+			if counter.Truncated != 0 {
+				log.Printf("Truncated %d of %d %s texts from %s", counter.Truncated, counter.Texts, field, source)
+			}
+		}
+	}
+
+	for _, source := range slices.Sorted(maps.Keys(r.Summaries)) {
+		counter := r.Summaries[source]
+		if counter.Summarized != 0 {
+			log.Printf("Summarized %d of %d articles from %s to %d%% of their length", counter.Summarized,
+				counter.Articles, source, counter.SummaryLength*100/counter.OriginalLength)
+		}
+	}
+}
+
+// write saves the report into dir, named after the time the run started.
+func (r *TruncationReport) write(dir string) error {
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("truncations_%s.json", r.GeneratedAt.Format("2006-01-02_15-04-05")))
+	return os.WriteFile(path, data, 0644)
+}
@@ -11,21 +11,38 @@ import (
 	"encoding/pem"
 	"log"
 	"os"
+	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/logrusorgru/aurora/v4"
 )
 
 type CountryConfig struct {
-	CountryCode  uint8  `json:"countryCode"`
+	CountryCode uint8 `json:"countryCode"`
+	// LanguageCode is the language of this file.
 	LanguageCode uint8  `json:"languageCode"`
 	Name         string `json:"name"`
 	Language     string `json:"language"`
-	Source       string `json:"source"`
+	// Languages lists the names of the languages the console offers for the country, in order. Every entry of a
+	// country lists the same languages, and each of them needs an entry of its own.
+	Languages []string `json:"languages"`
+	Source    string   `json:"source"`
+	// TopicPriority lists topic keys in the order they are preferred when a story was filed under several.
+	TopicPriority []string `json:"topicPriority,omitempty"`
+	// Topics lists the topics shown, in order. Topics left out are hidden, and names override the language's.
+	// Any topic the source fills can be listed. If it is empty, every built-in topic is shown with the language's names.
+	Topics []TopicConfig `json:"topics,omitempty"`
+}
+
+type TopicConfig struct {
+	Key  string `json:"key"`
+	Name string `json:"name,omitempty"`
 }
 
 type Countries struct {
-	Countries []CountryConfig `json:"countries"`
+	// TopicNames maps language names, as used by countries, to the name of each topic key.
+	TopicNames map[string]map[string]string `json:"topicNames"`
+	Countries  []CountryConfig              `json:"countries"`
 }
 
 func LoadCountries(filename string) (*Countries, error) {
@@ -48,6 +65,12 @@ func fixTime(value int) uint32 {
 	return uint32((value - 946684800) / 60)
 }
 
+// currentHourStart returns the start of the hour the news file is being generated for.
+func currentHourStart() time.Time {
+	t := time.Unix(int64(currentTime), 0)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.Local)
+}
+
 func SignFile(contents []byte, test bool) []byte {
 	buffer := new(bytes.Buffer)
 
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"NewsChannel/news"
+	"NewsChannel/store"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/logrusorgru/aurora/v4"
+)
+
+// runValidation checks the editable data files and the article store, printing every problem found.
+// It returns the exit code for the validate command.
+func runValidation(config *Config) int {
+	problems := news.ValidateLocationData(config.LocationDataPath)
+	problems = append(problems, news.ValidateBoilerplate(config.BoilerplatePath)...)
+	problems = append(problems, news.ValidateFeeds(config.FeedsPath)...)
+	// The topics of the training data, countries and summary rules are checked against the topics of the feeds.
+	if err := news.LoadFeeds(config.FeedsPath); err != nil {
+		problems = append(problems, err)
+	}
+	problems = append(problems, news.ValidateTopicData(config.TopicDataPath)...)
+	if err := config.TopicClassification.Validate(); err != nil {
+		problems = append(problems, fmt.Errorf("config.xml: TopicClassification: %w", err))
+	}
+	problems = append(problems, validateCountryLanguages()...)
+	problems = append(problems, validateTopicPriorities()...)
+	if err := config.ImagePipeline.Validate(); err != nil {
+		problems = append(problems, fmt.Errorf("config.xml: ImagePipeline: %w", err))
+	}
+	if err := config.TextLimits.Validate(); err != nil {
+		problems = append(problems, fmt.Errorf("config.xml: TextLimits: %w", err))
+	}
+	for _, rule := range config.Summaries {
+		if err := rule.Validate(); err != nil {
+			problems = append(problems, fmt.Errorf("config.xml: Summaries: %w", err))
+		}
+	}
+	if config.PictureFallbacks < 0 {
+		problems = append(problems, errors.New("config.xml: PictureFallbacks can't be negative"))
+	}
+	if config.ImageBudget < 0 {
+		problems = append(problems, errors.New("config.xml: ImageBudget can't be negative"))
+	}
+	problems = append(problems, validateArticleStore()...)
+
+	for _, problem := range problems {
+		log.Printf("%s", aurora.Red(problem.Error()))
+	}
+
+	if len(problems) != 0 {
+		log.Printf("Validation found %d problem(s)", len(problems))
+		return 1
+	}
+
+	log.Println("Validation passed")
+	return 0
+}
+
+// validateCountryLanguages makes sure every language code matches its language, that each country has a single
+// file per language, and that the languages a country offers are the ones it has files for.
+func validateCountryLanguages() []error {
+	countries, err := LoadCountries("countries.json")
+	if err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+	seen := make(map[[2]uint8]bool)
+	offered := make(map[uint8][]string)
+	for _, country := range countries.Countries {
+		code := country.LanguageCode
+		if int(code) >= len(languageNames) || languageNames[code] != country.Language {
+			problems = append(problems, fmt.Errorf("countries.json: %s (%s): language code %d is not %s", country.Name, country.Language, code, country.Language))
+		}
+
+		key := [2]uint8{country.CountryCode, code}
+		if seen[key] {
+			problems = append(problems, fmt.Errorf("countries.json: %s (%s): listed twice", country.Name, country.Language))
+		}
+		seen[key] = true
+
+		if !slices.Contains(country.Languages, country.Language) {
+			problems = append(problems, fmt.Errorf("countries.json: %s (%s): languages don't include %s", country.Name, country.Language, country.Language))
+		}
+		for _, language := range country.Languages {
+			if !slices.Contains(languageNames, language) {
+				problems = append(problems, fmt.Errorf("countries.json: %s (%s): unknown language %q", country.Name, country.Language, language))
+			}
+		}
+
+		if languages, ok := offered[country.CountryCode]; ok && !slices.Equal(languages, country.Languages) {
+			problems = append(problems, fmt.Errorf("countries.json: %s (%s): languages %v differ from %v in the country's other entries", country.Name, country.Language, country.Languages, languages))
+		}
+		offered[country.CountryCode] = country.Languages
+	}
+
+	// Every language a country offers needs a file.
+	checked := make(map[uint8]bool)
+	for _, country := range countries.Countries {
+		if checked[country.CountryCode] {
+			continue
+		}
+		checked[country.CountryCode] = true
+
+		for _, code := range country.LanguageCodes() {
+			if !seen[[2]uint8{country.CountryCode, code}] {
+				problems = append(problems, fmt.Errorf("countries.json: %s (%s): %s is offered but has no entry", country.Name, country.Language, languageNames[code]))
+			}
+		}
+	}
+
+	return problems
+}
+
+// validateTopicPriorities makes sure every country only lists topics it shows in its priority, and that the topics it
+// shows are named and filled by its source.
+func validateTopicPriorities() []error {
+	countries, err := LoadCountries("countries.json")
+	if err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+	for _, country := range countries.Countries {
+		labels, err := countries.TopicsFor(country)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("countries.json: %s (%s): %w", country.Name, country.Language, err))
+			continue
+		}
+
+		for _, key := range country.TopicPriority {
+			topic, err := news.ParseTopic(key)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("countries.json: %s (%s): %w", country.Name, country.Language, err))
+			} else if !slices.ContainsFunc(labels, func(label TopicLabel) bool { return label.Topic == topic }) {
+				problems = append(problems, fmt.Errorf("countries.json: %s (%s): priority topic %q is not shown", country.Name, country.Language, key))
+			}
+		}
+
+		topics := topicsOfSource(country.Source)
+		for _, label := range labels {
+			if !slices.Contains(topics, label.Topic) {
+				problems = append(problems, fmt.Errorf("countries.json: %s (%s): topic %q is not filled by the source", country.Name, country.Language, label.Topic))
+			}
+		}
+	}
+
+	return problems
+}
+
+// validateArticleStore makes sure article IDs are unique within a day for every country and language.
+func validateArticleStore() []error {
+	countries, err := LoadCountries("countries.json")
+	if err != nil {
+		return []error{err}
+	}
+
+	// Validation only reads, so it can run alongside a generator.
+	articleStore, err := store.OpenReadOnly(articleStorePath)
+	if err != nil {
+		return []error{err}
+	}
+	defer func(articleStore store.Store) {
+		err := articleStore.Close()
+		if err != nil {
+			log.Println("error closing article store:", err)
+		}
+	}(articleStore)
+
+	var problems []error
+	for _, countryConfig := range countries.Countries {
+		key := store.Key{
+			CountryCode:  countryConfig.CountryCode,
+			LanguageCode: countryConfig.LanguageCode,
+		}
+
+		articles, err := articleStore.Window(key, time.Time{}, time.Now().Add(time.Hour))
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+
+		// Articles are returned oldest first, so we only have to remember the last use of every ID.
+		lastUsed := make(map[uint32]time.Time)
+		for _, article := range articles {
+			hour := article.GeneratedAt.Local().Hour()
+			if article.ID < articleID(hour, 0) || article.ID >= articleID(hour+1, 0) {
+				problems = append(problems, fmt.Errorf("%s (%s): article %d is outside of the ID range of hour %d",
+					countryConfig.Name, countryConfig.Language, article.ID, hour))
+			}
+
+			if previous, exists := lastUsed[article.ID]; exists && article.GeneratedAt.Sub(previous) < 24*time.Hour {
+				problems = append(problems, fmt.Errorf("%s (%s): article %d generated at %s was already used at %s",
+					countryConfig.Name, countryConfig.Language, article.ID, article.GeneratedAt, previous))
+			}
+			lastUsed[article.ID] = article.GeneratedAt
+		}
+	}
+
+	return problems
+}